@@ -0,0 +1,217 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"halkyon.io/api/capability-info/v1beta1"
+	"halkyon.io/hal/pkg/k8s"
+	"halkyon.io/hal/pkg/log"
+	"io/ioutil"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultTTL controls how long the cached catalog is used before being refreshed from the cluster
+	defaultTTL = time.Hour
+	// ttlEnvVar can be set to a duration (e.g. 10m) to override the default TTL, 0 forcing a refresh every time
+	ttlEnvVar = "HAL_CATALOG_TTL"
+)
+
+// Runtime describes a runtime supported by the cluster along with its available versions
+type Runtime struct {
+	Name      string   `json:"name"`
+	Versions  []string `json:"versions"`
+	Generator string   `json:"generator,omitempty"`
+}
+
+// Catalog records which runtimes and capabilities are supported by the cluster, capabilities' versions being indexed by
+// category and type
+type Catalog struct {
+	Server       string                         `json:"server"`
+	Fetched      time.Time                      `json:"fetched"`
+	Runtimes     map[string]*Runtime            `json:"runtimes"`
+	Capabilities map[string]map[string][]string `json:"capabilities"`
+}
+
+var current *Catalog
+
+// Get retrieves the catalog of the cluster we're connected to, using the cached version if it is recent enough or if the
+// cluster cannot be reached, as long as the cached catalog was retrieved from that same cluster
+func Get() (*Catalog, error) {
+	if current != nil {
+		return current, nil
+	}
+
+	cached, cacheErr := load()
+	client, err := k8s.NewClient()
+	if err == nil {
+		server := client.Server()
+		if cached != nil && cached.Server == server && time.Since(cached.Fetched) < ttl() {
+			current = cached
+			return current, nil
+		}
+
+		var fetched *Catalog
+		if fetched, err = fetch(client); err == nil {
+			fetched.Server = server
+			if err := fetched.save(); err != nil {
+				log.Infof("Couldn't cache catalog: %v", err)
+			}
+			current = fetched
+			return current, nil
+		}
+		if cached != nil && cached.Server != server {
+			// the cached catalog describes another cluster
+			return nil, fmt.Errorf("couldn't retrieve catalog from %s: %v", server, err)
+		}
+	}
+
+	if cached == nil {
+		if cacheErr != nil && !os.IsNotExist(cacheErr) {
+			return nil, fmt.Errorf("couldn't retrieve catalog: %v (cache error: %v)", err, cacheErr)
+		}
+		return nil, fmt.Errorf("couldn't retrieve catalog: %v", err)
+	}
+	log.Infof("Using catalog cached on %s since the cluster cannot be reached: %v", cached.Fetched.Format(time.RFC822), err)
+	current = cached
+	return current, nil
+}
+
+// Cached retrieves the cached catalog without ever contacting the cluster, returning an empty catalog if none is cached
+func Cached() *Catalog {
+	if current != nil {
+		return current
+	}
+	cached, err := load()
+	if err != nil {
+		return &Catalog{}
+	}
+	return cached
+}
+
+func (c *Catalog) RuntimeNames() []string {
+	result := make([]string, 0, len(c.Runtimes))
+	for name := range c.Runtimes {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (c *Catalog) Categories() []string {
+	result := make([]string, 0, len(c.Capabilities))
+	for category := range c.Capabilities {
+		result = append(result, category)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (c *Catalog) TypesFor(category string) []string {
+	types := c.Capabilities[category]
+	result := make([]string, 0, len(types))
+	for t := range types {
+		result = append(result, t)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (c *Catalog) VersionsFor(category, capabilityType string) []string {
+	return c.Capabilities[category][capabilityType]
+}
+
+func fetch(client *k8s.Client) (*Catalog, error) {
+	catalog := &Catalog{
+		Fetched:      time.Now(),
+		Runtimes:     make(map[string]*Runtime, 11),
+		Capabilities: make(map[string]map[string][]string, 11),
+	}
+
+	runtimes, err := client.HalkyonRuntimeClient.Runtimes().List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range runtimes.Items {
+		name := item.Spec.Name
+		runtime, ok := catalog.Runtimes[name]
+		if !ok {
+			runtime = &Runtime{Name: name, Generator: item.Spec.GeneratorTemplate, Versions: make([]string, 0, 7)}
+			catalog.Runtimes[name] = runtime
+		}
+		runtime.Versions = append(runtime.Versions, item.Spec.Version)
+	}
+
+	infos, err := client.HalkyonCapabilityInfoClient.CapabilityInfos().List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range infos.Items {
+		category := item.Spec.Category
+		types, ok := catalog.Capabilities[category]
+		if !ok {
+			types = make(map[string][]string, 7)
+			catalog.Capabilities[category] = types
+		}
+		if _, ok := types[item.Spec.Type]; ok {
+			return nil, fmt.Errorf("a type named %s is already registered for category %s", item.Spec.Type, category)
+		}
+		types[item.Spec.Type] = strings.Split(item.Spec.Versions, v1beta1.CapabilityInfoVersionSeparator)
+	}
+
+	return catalog, nil
+}
+
+func ttl() time.Duration {
+	if value, ok := os.LookupEnv(ttlEnvVar); ok {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+		log.Infof("Ignoring invalid %s value '%s'", ttlEnvVar, value)
+	}
+	return defaultTTL
+}
+
+func cachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".hal", "catalog.json"), nil
+}
+
+func load() (*Catalog, error) {
+	path, err := cachePath()
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	catalog := &Catalog{}
+	if err := json.Unmarshal(bytes, catalog); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+func (c *Catalog) save() error {
+	path, err := cachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bytes, 0644)
+}
@@ -0,0 +1,69 @@
+package cmdutil
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"halkyon.io/hal/pkg/validation"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// CompletionFunc returns the candidate values for the argument or flag value being completed. Candidates don't need to
+// be filtered using the provided partial value, though implementations might use it to avoid expensive lookups.
+type CompletionFunc func(toComplete string) []string
+
+var (
+	flagCompletions = make(map[*pflag.Flag]CompletionFunc, 17)
+	argsCompletions = make(map[*cobra.Command]CompletionFunc, 7)
+)
+
+// RegisterFlagCompletion records how to complete values of the named flag of the specified command
+func RegisterFlagCompletion(cmd *cobra.Command, flagName string, fn CompletionFunc) {
+	flag := cmd.Flags().Lookup(flagName)
+	if flag == nil {
+		flag = cmd.PersistentFlags().Lookup(flagName)
+	}
+	if flag == nil {
+		panic(fmt.Errorf("no flag named %s exists on '%s' command", flagName, cmd.Name()))
+	}
+	flagCompletions[flag] = fn
+}
+
+// RegisterArgsCompletion records how to complete the positional arguments of the specified command
+func RegisterArgsCompletion(cmd *cobra.Command, fn CompletionFunc) {
+	argsCompletions[cmd] = fn
+}
+
+func FlagCompletion(flag *pflag.Flag) (CompletionFunc, bool) {
+	fn, ok := flagCompletions[flag]
+	return fn, ok
+}
+
+func ArgsCompletion(cmd *cobra.Command) (CompletionFunc, bool) {
+	fn, ok := argsCompletions[cmd]
+	return fn, ok
+}
+
+// EnumCompletion completes values using the known values of the specified EnumValue
+func EnumCompletion(enum validation.EnumValue) CompletionFunc {
+	return func(toComplete string) []string {
+		return enum.KnownValues()
+	}
+}
+
+// EntityNamesCompletion completes values using the names of the entities of the specified type existing in the cluster
+func EntityNamesCompletion(t ResourceType) CompletionFunc {
+	return func(toComplete string) []string {
+		entities, err := EntityFor(t).List()
+		if err != nil {
+			return nil
+		}
+		names := make([]string, 0, len(entities))
+		for _, entity := range entities {
+			if accessor, err := meta.Accessor(entity); err == nil {
+				names = append(names, accessor.GetName())
+			}
+		}
+		return names
+	}
+}
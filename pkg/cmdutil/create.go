@@ -80,7 +80,9 @@ func (o *CreateOptions) Complete(name string, cmd *cobra.Command, args []string)
 					}
 				}
 			} else if IsInteractive(cmd) && ui.Proceed(fmt.Sprintf("Found %d %s(s) in %s, do you want to %s from them", size, t, currentDirName, o.OperationName)) {
-				o.Name = ui.Select(t, names, o.Name)
+				if o.Name, err = ui.Select(t, names, o.Name); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -108,18 +110,26 @@ func (o *CreateOptions) Complete(name string, cmd *cobra.Command, args []string)
 	}
 
 	for {
-		o.Name = ui.Ask("Name", o.Name, o.generateName())
+		if o.Name, err = ui.Ask("Name", o.Name, o.generateName()); err != nil {
+			return err
+		}
 		if o.edit {
 			break
 		}
 		err := validation.NameValidator(o.Name)
 		if err != nil {
+			if !ui.IsInteractive() {
+				return fmt.Errorf("invalid name: '%s'", o.Name)
+			}
 			ui.OutputError(fmt.Sprintf("Invalid name: '%s', please select another one", o.Name))
 			o.Name = ""
 		}
 		if !o.edit {
 			exists, err := o.Exists()
 			if exists {
+				if !ui.IsInteractive() {
+					return fmt.Errorf("a %s named '%s' already exists", o.ResourceType, o.Name)
+				}
 				ui.OutputError(fmt.Sprintf("A %s named '%s' already exists, please select another name", o.ResourceType, o.Name))
 				o.Name = "" // reset name and try again!
 			} else {
@@ -5,13 +5,45 @@ import (
 	"github.com/spf13/cobra"
 	"halkyon.io/hal/pkg/log"
 	"halkyon.io/hal/pkg/ui"
+	"k8s.io/apimachinery/pkg/api/meta"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"os"
+	"sort"
+	"strings"
 )
 
 const deleteCommandName = "delete"
 
+// Deleter customizes the deletion of entities of a given type
+type Deleter interface {
+	// Plan checks that the named entities can be deleted, returning the other entities that need to be deleted along with
+	// them, or an error explaining why they cannot be deleted
+	Plan(names []string) ([]PlannedDeletion, error)
+}
+
+// PlannedDeletion is an entity that is about to be deleted
+type PlannedDeletion struct {
+	ResourceType ResourceType
+	Name         string
+	// Reason explains why the entity is deleted when it wasn't explicitly requested
+	Reason string
+}
+
+func (p PlannedDeletion) String() string {
+	s := fmt.Sprintf("%s '%s'", p.ResourceType, p.Name)
+	if len(p.Reason) > 0 {
+		s += " (" + p.Reason + ")"
+	}
+	return s
+}
+
 type DeleteOptions struct {
 	*GenericOperationOptions
+	Delegate Deleter
+	all      bool
+	selector string
+	names    []string
 }
 
 func NewDeleteOptions(resourceType ResourceType, client HalkyonEntity) *DeleteOptions {
@@ -31,11 +63,22 @@ func (o *DeleteOptions) Complete(name string, cmd *cobra.Command, args []string)
 	} else {
 		o.Name = args[0]
 	}
+	if o.isBulk() && len(o.Name) > 0 {
+		return fmt.Errorf("cannot specify a %s name when using --all or --selector", o.ResourceType)
+	}
 
 	return nil
 }
 
+func (o *DeleteOptions) isBulk() bool {
+	return o.all || len(o.selector) > 0
+}
+
 func (o *DeleteOptions) Validate() error {
+	if o.isBulk() {
+		return o.selectAll()
+	}
+
 	needName := len(o.Name) == 0
 	// if a name is provided, check that it corresponds to an existing component
 	if found, err := o.Exists(); !needName {
@@ -52,28 +95,177 @@ func (o *DeleteOptions) Validate() error {
 		if known.Len() == 0 {
 			return fmt.Errorf("no %s currently exist in '%s'", o.ResourceType, o.Client.GetNamespace())
 		}
+		if !ui.IsInteractive() {
+			if len(o.Name) == 0 {
+				return fmt.Errorf("missing name of the %s to delete, required when running non-interactively", o.ResourceType)
+			}
+			return fmt.Errorf("unknown %s '%s'", o.ResourceType, o.Name)
+		}
 		s := "Unknown " + o.ResourceType
 		if len(o.Name) == 0 {
 			s = "No provided " + o.ResourceType + " name"
 		}
 		message := ui.SelectFromOtherErrorMessage(s.String(), o.Name)
-		o.Name = ui.SelectDisplayable(message, known).Name()
+		selected, err := ui.SelectDisplayable(message, known)
+		if err != nil {
+			return err
+		}
+		o.Name = selected.Name()
+	}
+	o.names = []string{o.Name}
+	return nil
+}
+
+// selectAll records the names of all the existing entities matching the label selector, if any
+func (o *DeleteOptions) selectAll() error {
+	selector := labels.Everything()
+	if len(o.selector) > 0 {
+		var err error
+		if selector, err = labels.Parse(o.selector); err != nil {
+			return fmt.Errorf("invalid selector '%s': %v", o.selector, err)
+		}
+	}
+	objects, err := o.Client.List()
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		accessor, err := meta.Accessor(object)
+		if err != nil {
+			return err
+		}
+		if selector.Matches(labels.Set(accessor.GetLabels())) {
+			o.names = append(o.names, accessor.GetName())
+		}
 	}
+	if len(o.names) == 0 {
+		if len(o.selector) > 0 {
+			return fmt.Errorf("no %s matching '%s' currently exist in '%s'", o.ResourceType, o.selector, o.Client.GetNamespace())
+		}
+		return fmt.Errorf("no %s currently exist in '%s'", o.ResourceType, o.Client.GetNamespace())
+	}
+	sort.Strings(o.names)
 	return nil
 }
 
 func (o *DeleteOptions) Run() error {
-	if ui.Proceed(fmt.Sprintf("Really delete '%s' %s", o.Name, o.ResourceType)) {
-		err := o.Client.Delete(o.Name, &v1.DeleteOptions{})
-		if err == nil {
-			log.Successf("Successfully deleted '%s' %s", o.Name, o.ResourceType)
+	plan := make([]PlannedDeletion, 0, len(o.names))
+	for _, name := range o.names {
+		plan = append(plan, PlannedDeletion{ResourceType: o.ResourceType, Name: name})
+	}
+	if o.Delegate != nil {
+		dependents, err := o.Delegate.Plan(o.names)
+		if err != nil {
+			return err
 		}
+		plan = append(plan, dependents...)
+	}
+
+	message := fmt.Sprintf("Really delete '%s' %s", o.Name, o.ResourceType)
+	if len(plan) > 1 || o.isBulk() {
+		log.Infof("The following entities will be deleted from '%s':", o.Client.GetNamespace())
+		for _, deletion := range plan {
+			log.Progressf("%v", deletion)
+		}
+		message = fmt.Sprintf("Really delete these %d entities", len(plan))
+	}
+	if confirmed, err := ui.Confirm(message); err != nil {
 		return err
+	} else if !confirmed {
+		log.Errorf("Canceled deletion of %s", describePlan(plan))
+		return nil
+	}
+
+	deleted := make([]PlannedDeletion, 0, len(plan))
+	for _, deletion := range plan {
+		if err := EntityFor(deletion.ResourceType).Delete(deletion.Name, &v1.DeleteOptions{}); err != nil {
+			o.removeFromLocalDescriptors(deleted)
+			return fmt.Errorf("couldn't delete %v: %v", deletion, err)
+		}
+		log.Successf("Successfully deleted '%s' %s", deletion.Name, deletion.ResourceType)
+		deleted = append(deleted, deletion)
 	}
-	log.Errorf("Canceled deletion of '%s' %s", o.Name, o.ResourceType)
+	o.removeFromLocalDescriptors(deleted)
 	return nil
 }
 
+func describePlan(plan []PlannedDeletion) string {
+	if len(plan) == 1 {
+		return fmt.Sprintf("'%s' %s", plan[0].Name, plan[0].ResourceType)
+	}
+	names := make([]string, 0, len(plan))
+	for _, deletion := range plan {
+		names = append(names, deletion.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// removeFromLocalDescriptors removes the deleted entities from the local halkyon descriptors defining them, looking at
+// the current project if any, at the current directory and its children otherwise. Failing to do so is only reported
+// since the entities are already deleted from the cluster at this point.
+func (o *DeleteOptions) removeFromLocalDescriptors(deleted []PlannedDeletion) {
+	if len(deleted) == 0 {
+		return
+	}
+	project, err := FindCurrentProject()
+	if err != nil {
+		log.Errorf("Couldn't update local descriptors: %v", err)
+		return
+	}
+	var available *HalkyonDescriptor
+	if project != nil {
+		available = project.LoadEntities()
+	} else {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			log.Errorf("Couldn't update local descriptors: %v", err)
+			return
+		}
+		available = LoadAvailableHalkyonEntities(currentDir)
+	}
+
+	// group the deleted entities by descriptor so that each of them is only written once
+	byPath := make(map[string][]PlannedDeletion, len(deleted))
+	paths := make([]string, 0, len(deleted))
+	for _, deletion := range deleted {
+		local, ok := available.GetDefinedEntitiesWith(deletion.ResourceType)[deletion.Name]
+		if !ok {
+			continue
+		}
+		if IsGeneratedDescriptor(local.Path) {
+			log.Infof("'%s' %s also needs to be removed from the sources generating %s", deletion.Name, deletion.ResourceType, local.Path)
+			continue
+		}
+		if _, ok := byPath[local.Path]; !ok {
+			paths = append(paths, local.Path)
+		}
+		byPath[local.Path] = append(byPath[local.Path], deletion)
+	}
+	for _, path := range paths {
+		descriptor, err := LoadHalkyonDescriptor(path)
+		if err != nil {
+			log.Errorf("Couldn't update %s: %v", path, err)
+			continue
+		}
+		for _, deletion := range byPath[path] {
+			descriptor.Remove(deletion.ResourceType, deletion.Name)
+		}
+		if err := descriptor.OutputAt(); err != nil {
+			log.Errorf("Couldn't update %s: %v", path, err)
+			continue
+		}
+		log.Infof("Removed %s from %s", describePlan(byPath[path]), path)
+	}
+}
+
 func NewGenericDelete(fullParentName string, o *DeleteOptions) *cobra.Command {
-	return NewGenericOperation(fullParentName, o.GenericOperationOptions)
+	cmd := NewGenericOperation(fullParentName, o.GenericOperationOptions)
+	cmd.Long = fmt.Sprintf(`%s.
+
+Deleted entities are also removed from the local halkyon descriptors defining them. When deleting several entities,
+the list of entities to delete is displayed before asking for confirmation.`, cmd.Short)
+	cmd.Flags().BoolVar(&o.all, "all", false, fmt.Sprintf("Delete all %s entities of the current namespace", o.ResourceType))
+	cmd.Flags().StringVarP(&o.selector, "selector", "l", "", fmt.Sprintf("Delete the %s entities matching the specified label selector, e.g. app=foo", o.ResourceType))
+	RegisterArgsCompletion(cmd, EntityNamesCompletion(o.ResourceType))
+	return cmd
 }
@@ -81,6 +81,16 @@ func (hd *HalkyonDescriptor) addNewEntity(object runtime.Object, name, path stri
 	hdMap[name] = newHalkyonDescriptorEntity(object, name, path)
 }
 
+// Remove removes the named entity of the specified type from the descriptor, returning whether it was defined there
+func (hd *HalkyonDescriptor) Remove(t ResourceType, name string) bool {
+	registry := hd.entitiesByType[t]
+	if _, ok := registry[name]; !ok {
+		return false
+	}
+	delete(registry, name)
+	return true
+}
+
 func (hd *HalkyonDescriptor) mergeWith(descriptor *HalkyonDescriptor) {
 	for _, registry := range descriptor.entitiesByType {
 		for _, entity := range registry {
@@ -210,6 +220,12 @@ func (hd *HalkyonDescriptor) OutputAt(path ...string) error {
 	return ioutil.WriteFile(p, bytes, 0644)
 }
 
+// IsGeneratedDescriptor checks whether the descriptor at the specified path was generated by dekorate, in which case it
+// shouldn't be modified since it would be overwritten by the next build
+func IsGeneratedDescriptor(path string) bool {
+	return filepath.Base(filepath.Dir(path)) == "dekorate"
+}
+
 func halkyonDescriptorFrom(path, extension string) string {
 	return filepath.Join(path, "target", "classes", "META-INF", "dekorate", descriptorName(extension))
 }
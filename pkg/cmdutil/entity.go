@@ -104,6 +104,23 @@ type HalkyonEntity interface {
 	Get(name string) (runtime.Object, error)
 	Create(runtime.Object) error
 	Delete(string, *v1.DeleteOptions) error
+	List() ([]runtime.Object, error)
 	GetKnown() ui.DisplayableMap
 	GetNamespace() string
 }
+
+var entities = make(map[ResourceType]HalkyonEntity, 2)
+
+// RegisterEntity records the client to use to interact with entities of the specified type
+func RegisterEntity(t ResourceType, client HalkyonEntity) {
+	entities[t] = client
+}
+
+// EntityFor retrieves the client to use to interact with entities of the specified type
+func EntityFor(t ResourceType) HalkyonEntity {
+	client, ok := entities[t]
+	if !ok {
+		panic(fmt.Errorf("no client registered for %s entities", t))
+	}
+	return client
+}
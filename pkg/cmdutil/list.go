@@ -0,0 +1,143 @@
+package cmdutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/hal/pkg/log"
+	"halkyon.io/hal/pkg/validation"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+	"strings"
+	"text/tabwriter"
+)
+
+const listCommandName = "list"
+
+// Lister knows how to display entities of a given type as rows of a table
+type Lister interface {
+	Headers() []string
+	Row(object runtime.Object) []string
+}
+
+type ListOptions struct {
+	*GenericOperationOptions
+	Delegate Lister
+	output   validation.EnumValue
+	entities []runtime.Object
+}
+
+func NewListOptions(resourceType ResourceType, client HalkyonEntity, lister Lister) *ListOptions {
+	l := &ListOptions{
+		Delegate: lister,
+		output:   validation.NewEnumValue("output", "table", "json", "yaml", "name"),
+	}
+	l.GenericOperationOptions = &GenericOperationOptions{
+		ResourceType:  resourceType,
+		Client:        client,
+		OperationName: listCommandName,
+		delegate:      l,
+	}
+	return l
+}
+
+func (o *ListOptions) Complete(name string, cmd *cobra.Command, args []string) error {
+	if len(o.output.Provided) == 0 {
+		o.output.Provided = "table"
+	}
+	return nil
+}
+
+func (o *ListOptions) Validate() error {
+	return o.output.Contains(o.output.Provided)
+}
+
+func (o *ListOptions) Run() (err error) {
+	o.entities, err = o.Client.List()
+	if err != nil {
+		return err
+	}
+
+	switch o.output.Provided {
+	case "json", "yaml":
+		return o.outputList()
+	case "name":
+		for _, entity := range o.entities {
+			if accessor, err := meta.Accessor(entity); err == nil {
+				fmt.Fprintln(log.GetStdout(), accessor.GetName())
+			}
+		}
+		return nil
+	default:
+		if len(o.entities) == 0 {
+			log.Infof("No %s found in '%s'", o.ResourceType, o.Client.GetNamespace())
+			return nil
+		}
+		return o.outputTable()
+	}
+}
+
+func (o *ListOptions) outputTable() error {
+	w := tabwriter.NewWriter(log.GetStdout(), 0, 4, 3, ' ', 0)
+	headers := o.Delegate.Headers()
+	for i, header := range headers {
+		headers[i] = strings.ToUpper(header)
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, entity := range o.entities {
+		fmt.Fprintln(w, strings.Join(o.Delegate.Row(entity), "\t"))
+	}
+	return w.Flush()
+}
+
+func (o *ListOptions) outputList() error {
+	list := v1.List{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "List",
+			APIVersion: "v1",
+		},
+		Items: make([]runtime.RawExtension, 0, len(o.entities)),
+	}
+	for _, entity := range o.entities {
+		list.Items = append(list.Items, runtime.RawExtension{Object: entity})
+	}
+
+	var bytes []byte
+	var err error
+	if o.output.Provided == "json" {
+		bytes, err = json.MarshalIndent(list, "", "  ")
+	} else {
+		bytes, err = yaml.Marshal(list)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(log.GetStdout(), string(bytes))
+	return nil
+}
+
+func NewGenericList(fullParentName string, o *ListOptions) *cobra.Command {
+	example := ktemplates.Examples(`  # List the %[1]ss of the current namespace
+  %[2]s
+
+  # List the names of the %[1]ss of the current namespace, one per line
+  %[2]s -o name`)
+	cmd := &cobra.Command{
+		Use:     fmt.Sprintf("%s [flags]", listCommandName),
+		Short:   fmt.Sprintf("List the %ss of the current namespace", o.ResourceType),
+		Long:    fmt.Sprintf("List the %ss of the current namespace.", o.ResourceType),
+		Example: fmt.Sprintf(example, o.ResourceType, CommandName(listCommandName, fullParentName)),
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			GenericRun(o, cmd, args)
+		},
+	}
+	cmd.Flags().StringVarP(&o.output.Provided, "output", "o", "table", "Output format. Possible values: "+o.output.GetKnownValues())
+	RegisterFlagCompletion(cmd, "output", EnumCompletion(o.output))
+	return cmd
+}
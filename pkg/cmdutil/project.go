@@ -0,0 +1,177 @@
+package cmdutil
+
+import (
+	"fmt"
+	component "halkyon.io/api/component/v1beta1"
+	halkyon "halkyon.io/api/v1beta1"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+// ProjectFileName is the name of the file grouping components into an application, usually located at the root of a
+// repository. It is looked for in the current directory and its parents.
+const ProjectFileName = "hal-project.yml"
+
+// Project groups the components making up an application along with the capabilities and environment they share
+type Project struct {
+	Name       string          `json:"name,omitempty"`
+	Components []ProjectMember `json:"components"`
+	// Capabilities lists the capabilities shared by the application's components
+	Capabilities []ProjectMember `json:"capabilities,omitempty"`
+	// Env lists environment variables set on all components, values defined by a component taking precedence
+	Env []halkyon.NameValuePair `json:"env,omitempty"`
+	// dir is the directory containing the project file, which members' paths are relative to
+	dir string
+}
+
+// ProjectMember is a component or capability of a project
+type ProjectMember struct {
+	Name string `json:"name"`
+	// Path is the directory holding the member's halkyon descriptor and, for components, their sources, relative to the
+	// project file. It defaults to the member's name for components and to the project's directory for capabilities.
+	Path string `json:"path,omitempty"`
+}
+
+// FindProject looks for a project file in the specified directory and its parents, returning nil if none is found
+func FindProject(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if _, err := os.Stat(path); err == nil {
+			return LoadProject(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// FindCurrentProject looks for a project file in the current directory and its parents, returning nil if none is found
+func FindCurrentProject() (*Project, error) {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return FindProject(currentDir)
+}
+
+// LoadProject loads and validates the project file at the specified path
+func LoadProject(path string) (*Project, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &Project{}
+	if err := yaml.Unmarshal(content, p); err != nil {
+		return nil, fmt.Errorf("invalid project file %s: %v", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid project file %s: %v", path, err)
+	}
+	p.dir = filepath.Dir(path)
+	return p, nil
+}
+
+func (p *Project) validate() error {
+	for kind, members := range map[string][]ProjectMember{"component": p.Components, "capability": p.Capabilities} {
+		names := make(map[string]bool, len(members))
+		for _, member := range members {
+			if len(member.Name) == 0 {
+				return fmt.Errorf("all %s entries must have a name", kind)
+			}
+			if names[member.Name] {
+				return fmt.Errorf("%s '%s' is listed several times", kind, member.Name)
+			}
+			names[member.Name] = true
+		}
+	}
+	for _, env := range p.Env {
+		if len(env.Name) == 0 {
+			return fmt.Errorf("all env entries must have a name")
+		}
+	}
+	return nil
+}
+
+// Dir returns the directory containing the project file
+func (p *Project) Dir() string {
+	return p.dir
+}
+
+// ComponentNames returns the names of the project's components, in the order they're listed
+func (p *Project) ComponentNames() []string {
+	names := make([]string, 0, len(p.Components))
+	for _, member := range p.Components {
+		names = append(names, member.Name)
+	}
+	return names
+}
+
+// ComponentPath returns the absolute path of the directory of the named component, if it belongs to the project
+func (p *Project) ComponentPath(name string) (string, bool) {
+	for _, member := range p.Components {
+		if member.Name == name {
+			return p.componentPath(member), true
+		}
+	}
+	return "", false
+}
+
+// ComponentAt returns the name of the project's component whose directory contains the specified path, if any
+func (p *Project) ComponentAt(path string) (string, bool) {
+	for _, member := range p.Components {
+		dir := p.componentPath(member)
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return member.Name, true
+		}
+	}
+	return "", false
+}
+
+func (p *Project) componentPath(member ProjectMember) string {
+	if len(member.Path) == 0 {
+		return filepath.Join(p.dir, member.Name)
+	}
+	return filepath.Join(p.dir, filepath.FromSlash(member.Path))
+}
+
+func (p *Project) capabilityPath(member ProjectMember) string {
+	return filepath.Join(p.dir, filepath.FromSlash(member.Path))
+}
+
+// LoadEntities loads the entities defined in the halkyon descriptors of the project's members, as well as the ones found
+// in the project's directory, applying the shared environment to components
+func (p *Project) LoadEntities() *HalkyonDescriptor {
+	hd := LoadAvailableHalkyonEntities(p.dir)
+	for _, member := range p.Components {
+		hd.addEntitiesFromDir(p.componentPath(member))
+	}
+	for _, member := range p.Capabilities {
+		hd.addEntitiesFromDir(p.capabilityPath(member))
+	}
+	for _, entity := range hd.GetDefinedEntitiesWith(Component) {
+		p.ApplySharedEnv(entity.Entity.(*component.Component))
+	}
+	return hd
+}
+
+// ApplySharedEnv adds the project's environment variables that the specified component doesn't already define
+func (p *Project) ApplySharedEnv(c *component.Component) {
+	defined := make(map[string]bool, len(c.Spec.Envs))
+	for _, env := range c.Spec.Envs {
+		defined[env.Name] = true
+	}
+	for _, env := range p.Env {
+		if !defined[env.Name] {
+			c.Spec.Envs = append(c.Spec.Envs, env)
+		}
+	}
+}
@@ -3,7 +3,11 @@ package cmdutil
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"halkyon.io/hal/pkg/io"
+	"halkyon.io/hal/pkg/ui"
+	"os"
+	"strings"
 )
 
 type Runnable interface {
@@ -12,8 +16,49 @@ type Runnable interface {
 	Run() error
 }
 
+// GenericRun completes, validates then runs the specified Runnable, exiting with an error if any of these steps fails
 func GenericRun(o Runnable, cmd *cobra.Command, args []string) {
-	io.LogErrorAndExit(o.Complete(cmd.Name(), cmd, args), fmt.Sprintf("error completing %s", cmd.Name()))
-	io.LogErrorAndExit(o.Validate(), fmt.Sprintf("error validating %s", cmd.Name()))
-	io.LogErrorAndExit(o.Run(), fmt.Sprintf("error running %s", cmd.Name()))
+	io.LogErrorAndExit(withPromptFlag(cmd, o.Complete(cmd.Name(), cmd, args)), fmt.Sprintf("error completing %s", cmd.Name()))
+	io.LogErrorAndExit(withPromptFlag(cmd, o.Validate()), fmt.Sprintf("error validating %s", cmd.Name()))
+	err := o.Run()
+	if exit, ok := err.(ExitStatusError); ok {
+		os.Exit(exit.Status)
+	}
+	io.LogErrorAndExit(withPromptFlag(cmd, err), fmt.Sprintf("error running %s", cmd.Name()))
+}
+
+// withPromptFlag records which flag of the specified command can provide the value of a prompt that couldn't be
+// displayed, if the specified error reports such a prompt
+func withPromptFlag(cmd *cobra.Command, err error) error {
+	if promptErr, ok := err.(*ui.PromptError); ok {
+		promptErr.Flag = flagForPrompt(cmd, promptErr.Prompt)
+	}
+	return err
+}
+
+// ExitStatusError can be returned by Runnables to make hal exit with a specific status without logging anything, e.g. to
+// pass through the exit status of a command executed in a component or to report differences like diff tools do
+type ExitStatusError struct {
+	Status  int
+	Message string
+}
+
+func (e ExitStatusError) Error() string {
+	return e.Message
+}
+
+// flagForPrompt looks for the flag of the specified command that provides the value asked by the specified prompt, e.g.
+// --groupid for "Group Id", returning an empty name if there is no such flag
+func flagForPrompt(cmd *cobra.Command, prompt string) string {
+	normalize := func(s string) string {
+		return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToLower(s))
+	}
+	prompt = normalize(prompt)
+	name := ""
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if normalize(flag.Name) == prompt {
+			name = flag.Name
+		}
+	})
+	return name
 }
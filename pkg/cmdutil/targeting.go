@@ -7,10 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 )
 
 type ComponentTargetingOptions struct {
 	paths    []string
+	all      bool
 	current  targetComponent
 	targets  []targetComponent
 	runnable Runnable
@@ -22,13 +24,44 @@ type targetComponent struct {
 	name       string
 }
 
-func initTargetComponent(path string) (tc targetComponent, err error) {
-	// check that we have an halkyon descriptor
-	descriptor := halkyonDescriptorFrom(path, "yml")
-	tc.name = filepath.Base(path)
-	tc.path = path
-	tc.descriptor = descriptor
-	return tc, nil
+func initTargetComponent(path, name string) targetComponent {
+	return targetComponent{path: path, name: name, descriptor: halkyonDescriptorFrom(path, "yml")}
+}
+
+// resolveTarget identifies the component targeted by the specified value, either the name of a component of the
+// specified project, if any, or a directory relative to the current one
+func resolveTarget(project *Project, currentDir, value string) (targetComponent, error) {
+	if project != nil {
+		if path, ok := project.ComponentPath(value); ok {
+			if !validation.IsValidDir(path) {
+				return targetComponent{}, fmt.Errorf("%s directory of '%s' component doesn't exist", path, value)
+			}
+			return initTargetComponent(path, value), nil
+		}
+	}
+	path := filepath.Join(currentDir, value)
+	if !validation.IsValidDir(path) {
+		return targetComponent{}, fmt.Errorf("%s doesn't exist", path)
+	}
+	return targetAt(project, path), nil
+}
+
+// targetAt identifies the component located at the specified path, using the name declared in the specified project if
+// the path belongs to one of its components, the directory's name otherwise
+func targetAt(project *Project, path string) targetComponent {
+	if project != nil {
+		if name, ok := project.ComponentAt(path); ok {
+			componentPath, _ := project.ComponentPath(name)
+			return initTargetComponent(componentPath, name)
+		}
+	}
+	return initTargetComponent(path, filepath.Base(path))
+}
+
+// AllTargetsRunnable can be implemented by Runnables that need to process all targeted components at once (e.g. to keep
+// watching them) instead of having their Run function called once per target
+type AllTargetsRunnable interface {
+	RunOnAllTargets() error
 }
 
 type withTargeting interface {
@@ -66,40 +99,52 @@ func NewTargetingOptions() *ComponentTargetingOptions {
 }
 
 func (o *ComponentTargetingOptions) Complete(name string, cmd *cobra.Command, args []string) error {
-	// todo: separate component identification logic from path / directory
 	currentDir, err := os.Getwd()
 	if err != nil {
 		return err
 	}
+	// components are identified by their name in the project file, if any, and by their directory otherwise
+	project, err := FindProject(currentDir)
+	if err != nil {
+		return err
+	}
 
-	targetNb := len(o.paths)
-	if targetNb > 0 {
-		o.targets = make([]targetComponent, 0, targetNb)
-		for _, path := range o.paths {
-			path = filepath.Join(currentDir, path)
-			if !validation.IsValidDir(path) {
-				return fmt.Errorf("%s doesn't exist", path)
-			}
-			// set current target
-			o.current, err = initTargetComponent(path)
+	switch {
+	case o.all:
+		if project == nil {
+			return fmt.Errorf("--all requires a %s project file in the current directory or one of its parents", ProjectFileName)
+		}
+		if len(o.paths) > 0 {
+			return fmt.Errorf("--all and --components cannot be used together")
+		}
+		for _, name := range project.ComponentNames() {
+			target, err := resolveTarget(project, currentDir, name)
 			if err != nil {
 				return err
 			}
-			// record target for later reuse
-			o.targets = append(o.targets, o.current)
-			err := o.runnable.Complete(name, cmd, args)
+			o.targets = append(o.targets, target)
+		}
+	case len(o.paths) > 0:
+		o.targets = make([]targetComponent, 0, len(o.paths))
+		for _, path := range o.paths {
+			target, err := resolveTarget(project, currentDir, path)
 			if err != nil {
 				return err
 			}
+			o.targets = append(o.targets, target)
 		}
-	} else {
-		o.current, err = initTargetComponent(currentDir)
-		if err != nil {
-			return err
-		}
+	default:
+		o.current = targetAt(project, currentDir)
 		return o.runnable.Complete(name, cmd, args)
 	}
 
+	for _, target := range o.targets {
+		// set current target
+		o.current = target
+		if err := o.runnable.Complete(name, cmd, args); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -108,9 +153,34 @@ func (o *ComponentTargetingOptions) Validate() error {
 }
 
 func (o *ComponentTargetingOptions) Run() error {
+	if all, ok := o.runnable.(AllTargetsRunnable); ok {
+		return all.RunOnAllTargets()
+	}
 	return o.runForEachPath(o.runnable.Run)
 }
 
+// ForEachTarget calls the provided function once per targeted component, making each target the current one before the
+// call. Processing stops at the first error.
+func (o *ComponentTargetingOptions) ForEachTarget(fn func() error) error {
+	return o.runForEachPath(fn)
+}
+
+// Targets returns options targeting each of the targeted components individually so that they can be processed
+// independently, e.g. concurrently
+func (o *ComponentTargetingOptions) Targets() []*ComponentTargetingOptions {
+	if len(o.targets) == 0 {
+		return []*ComponentTargetingOptions{o}
+	}
+	targets := make([]*ComponentTargetingOptions, 0, len(o.targets))
+	for _, target := range o.targets {
+		single := *o
+		single.current = target
+		single.targets = nil
+		targets = append(targets, &single)
+	}
+	return targets
+}
+
 func (o *ComponentTargetingOptions) runForEachPath(fn func() error) error {
 	if len(o.targets) > 0 {
 		for _, target := range o.targets {
@@ -127,5 +197,24 @@ func (o *ComponentTargetingOptions) runForEachPath(fn func() error) error {
 }
 
 func (o *ComponentTargetingOptions) AttachFlagTo(cmd *cobra.Command) {
-	cmd.Flags().StringSliceVarP(&o.paths, "components", "c", nil, "Execute the command on the target component(s) instead of the current one")
+	cmd.Flags().StringSliceVarP(&o.paths, "components", "c", nil, "Execute the command on the target component(s), identified by name in the project file or by directory, instead of the current one")
+	cmd.Flags().BoolVar(&o.all, "all", false, "Execute the command on all the components listed in the "+ProjectFileName+" project file")
+	RegisterFlagCompletion(cmd, "components", componentTargetCompletion)
+}
+
+// componentTargetCompletion completes targeted components with the names of the components of the current project, if
+// any, and of the ones existing in the cluster
+func componentTargetCompletion(toComplete string) []string {
+	names := EntityNamesCompletion(Component)(toComplete)
+	if project, err := FindCurrentProject(); err == nil && project != nil {
+		names = append(names, project.ComponentNames()...)
+	}
+	sort.Strings(names)
+	unique := names[:0]
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			unique = append(unique, name)
+		}
+	}
+	return unique
 }
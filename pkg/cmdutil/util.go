@@ -1,6 +1,11 @@
 package cmdutil
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"halkyon.io/hal/pkg/ui"
+)
 
 func CommandName(name, fullParentName string) string {
 	return fullParentName + " " + name
@@ -12,6 +17,29 @@ func FlagValueIfSet(cmd *cobra.Command, flagName string) string {
 	return flag
 }
 
+// IsInteractive determines whether optional values should be prompted for when running the given command
 func IsInteractive(cmd *cobra.Command) bool {
-	return cmd.Flags().NFlag() <= 2 // heuristics to determine whether we're running in interactive mode
+	// if several flags were provided, assume the user provided everything they wanted to, ignoring global flags such as
+	// --namespace or -y which don't say anything about the values the command needs
+	provided := 0
+	cmd.LocalFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed {
+			provided++
+		}
+	})
+	return ui.IsInteractive() && provided <= 2
+}
+
+// CheckRequiredFlags makes sure that the specified flags are set when running non-interactively, since their value
+// cannot be prompted for
+func CheckRequiredFlags(cmd *cobra.Command, flagNames ...string) error {
+	if ui.IsInteractive() {
+		return nil
+	}
+	for _, name := range flagNames {
+		if flag := cmd.Flags().Lookup(name); flag != nil && !flag.Changed {
+			return fmt.Errorf("missing --%s flag, required when running non-interactively", name)
+		}
+	}
+	return nil
 }
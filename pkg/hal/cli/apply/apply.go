@@ -0,0 +1,158 @@
+package apply
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/log"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+const commandName = "apply"
+
+type options struct {
+	file       string
+	descriptor *cmdutil.HalkyonDescriptor
+	results    []result
+}
+
+type result struct {
+	resourceType cmdutil.ResourceType
+	name         string
+	outcome      string
+	err          error
+}
+
+var (
+	applyExample = ktemplates.Examples(`  # Create or update all the components and capabilities defined in the current and child directories or, if a
+  # hal-project.yml file is found in the current directory or one of its parents, by the project's members
+  %[1]s
+
+  # Create or update the components and capabilities defined in the specified descriptor
+  %[1]s -f halkyon.yml`)
+)
+
+func (o *options) Complete(name string, cmd *cobra.Command, args []string) error {
+	if len(o.file) > 0 {
+		descriptor, err := cmdutil.LoadHalkyonDescriptor(o.file)
+		if err != nil {
+			return fmt.Errorf("couldn't load descriptor %s: %v", o.file, err)
+		}
+		o.descriptor = descriptor
+		return nil
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	project, err := cmdutil.FindProject(currentDir)
+	if err != nil {
+		return err
+	}
+	if project != nil {
+		log.Infof("Applying entities of the project defined in %s", project.Dir())
+		o.descriptor = project.LoadEntities()
+		return nil
+	}
+	o.descriptor = cmdutil.LoadAvailableHalkyonEntities(currentDir)
+	return nil
+}
+
+func (o *options) Validate() error {
+	if o.descriptor.IsEmpty() {
+		return fmt.Errorf("no components or capabilities are defined")
+	}
+	return nil
+}
+
+func (o *options) Run() error {
+	// known resource types are ordered so that capabilities are created before the components that require them
+	failed := 0
+	for _, t := range cmdutil.KnownResourceTypes() {
+		entities := o.descriptor.GetDefinedEntitiesWith(t)
+		names := make([]string, 0, len(entities))
+		for name := range entities {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		client := cmdutil.EntityFor(t)
+		for _, name := range names {
+			r := result{resourceType: t, name: name}
+			r.outcome, r.err = applyEntity(client, entities[name])
+			if r.err != nil {
+				failed++
+				log.Errorf("Couldn't apply '%s' %s: %v", name, t, r.err)
+			} else {
+				log.Successf("Successfully %s '%s' %s", r.outcome, name, t)
+			}
+			o.results = append(o.results, r)
+		}
+	}
+
+	o.outputSummary()
+	if failed > 0 {
+		return fmt.Errorf("%d out of %d entities couldn't be applied", failed, len(o.results))
+	}
+	return nil
+}
+
+func applyEntity(client cmdutil.HalkyonEntity, entity cmdutil.HalkyonDescriptorEntity) (string, error) {
+	accessor, err := meta.Accessor(entity.Entity)
+	if err != nil {
+		return "", err
+	}
+	// entities are created in the namespace we're currently connected to
+	accessor.SetNamespace(client.GetNamespace())
+
+	outcome := "updated"
+	if _, err := client.Get(entity.Name); err != nil {
+		if !errors.IsNotFound(err) {
+			return "", err
+		}
+		outcome = "created"
+	}
+	if err := client.Create(entity.Entity); err != nil {
+		return "", err
+	}
+	return outcome, nil
+}
+
+func (o *options) outputSummary() {
+	w := tabwriter.NewWriter(log.GetStdout(), 0, 4, 3, ' ', 0)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "KIND\tNAME\tRESULT")
+	for _, r := range o.results {
+		outcome := r.outcome
+		if r.err != nil {
+			outcome = "failed: " + r.err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.resourceType, r.name, outcome)
+	}
+	_ = w.Flush()
+}
+
+func NewCmdApply(parent string) *cobra.Command {
+	o := &options{}
+	apply := &cobra.Command{
+		Use:   fmt.Sprintf("%s [flags]", commandName),
+		Short: "Create or update all the components and capabilities defined in halkyon descriptors",
+		Long: `Create or update all the components and capabilities defined in halkyon descriptors, creating capabilities before the components that require them.
+
+When a hal-project.yml project file is found in the current directory or one of its parents, the descriptors of the
+project's members are applied, the environment variables it defines being added to all components.`,
+		Example: fmt.Sprintf(applyExample, cmdutil.CommandName(commandName, parent)),
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.GenericRun(o, cmd, args)
+		},
+	}
+	apply.Flags().StringVarP(&o.file, "file", "f", "", "Halkyon descriptor to apply instead of the ones found in the current and child directories")
+	return apply
+}
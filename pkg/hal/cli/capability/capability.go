@@ -23,6 +23,7 @@ func NewCmdCapability(parent string) *cobra.Command {
 	hal.AddCommand(
 		create,
 		del,
+		NewCmdList(fullName),
 	)
 
 	return hal
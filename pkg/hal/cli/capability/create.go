@@ -3,31 +3,24 @@ package capability
 import (
 	"fmt"
 	"github.com/spf13/cobra"
-	v1beta12 "halkyon.io/api/capability-info/v1beta1"
 	"halkyon.io/api/capability/v1beta1"
 	halkyon "halkyon.io/api/v1beta1"
+	"halkyon.io/hal/pkg/catalog"
 	"halkyon.io/hal/pkg/cmdutil"
-	"halkyon.io/hal/pkg/k8s"
 	"halkyon.io/hal/pkg/ui"
 	"halkyon.io/hal/pkg/validation"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ktemplates "k8s.io/kubectl/pkg/util/templates"
-	"sort"
 	"strings"
 )
 
-type typeRegistry map[string][]string
-type categoryRegisty map[string]typeRegistry
-
-var categories = <-getCapabilityInfos()
-
 type CapabilityCreateOptions struct {
 	category    string
 	subCategory string
 	version     string
 	paramPairs  []string
 	parameters  []halkyon.NameValuePair
+	catalog     *catalog.Catalog
 }
 
 func (c CapabilityCreateOptions) AsCapabilitySpec() v1beta1.CapabilitySpec {
@@ -60,19 +53,16 @@ func (o *createOptions) GeneratePrefix() string {
 
 func (o *createOptions) Build() runtime.Object {
 	if o.target == nil {
-		o.target = &v1beta1.Capability{
-			TypeMeta: typeMeta(),
-			ObjectMeta: v1.ObjectMeta{
-				Name:      o.Name,
-				Namespace: o.CreateOptions.Client.GetNamespace(),
-			},
-			Spec: o.AsCapabilitySpec(),
-		}
+		o.target = New(o.Name, o.AsCapabilitySpec())
+		o.target.Namespace = o.CreateOptions.Client.GetNamespace()
 	}
 	return o.target
 }
 
 func (o *createOptions) Complete(name string, cmd *cobra.Command, args []string) error {
+	if err := cmdutil.CheckRequiredFlags(cmd, "category", "type", "version"); err != nil {
+		return err
+	}
 	return o.CapabilityCreateOptions.Complete()
 }
 
@@ -81,9 +71,21 @@ func (o *createOptions) Validate() error {
 }
 
 func (c *CapabilityCreateOptions) Complete() error {
-	ui.SelectOrCheckExisting(&c.category, "Category", c.getCategories(), c.isValidCategory)
-	ui.SelectOrCheckExisting(&c.subCategory, "Type", c.getTypesFor(c.category), c.isValidTypeGivenCategory)
-	ui.SelectOrCheckExisting(&c.version, "Version", c.getVersionsFor(c.category, c.subCategory), c.isValidVersionGivenCategoryAndType)
+	cat, err := catalog.Get()
+	if err != nil {
+		return err
+	}
+	c.catalog = cat
+
+	if err := ui.SelectOrCheckExisting(&c.category, "Category", c.getCategories(), c.isValidCategory); err != nil {
+		return err
+	}
+	if err := ui.SelectOrCheckExisting(&c.subCategory, "Type", c.getTypesFor(c.category), c.isValidTypeGivenCategory); err != nil {
+		return err
+	}
+	if err := ui.SelectOrCheckExisting(&c.version, "Version", c.getVersionsFor(c.category, c.subCategory), c.isValidVersionGivenCategoryAndType); err != nil {
+		return err
+	}
 
 	for _, pair := range c.paramPairs {
 		if e := c.addToParams(pair); e != nil {
@@ -109,16 +111,24 @@ func (c *CapabilityCreateOptions) Validate() error {
 	// first deal with required params
 	for _, info := range infos {
 		if info.Required {
-			c.addValueFor(info)
+			if !ui.IsInteractive() && !c.hasParameter(info.name) {
+				return fmt.Errorf("missing value for required %s parameter, use --parameters %s=<value>", info.name, info.name)
+			}
+			if err := c.addValueFor(info); err != nil {
+				return err
+			}
 			// remove property from list of properties to consider
 			delete(params, info.name)
 		}
 	}
 
-	// finally check if we still have capability parameters that have not been considered
+	// finally check if we still have capability parameters that have not been considered, non-required parameters being
+	// left unset when running non-interactively
 	if len(params) > 0 && ui.Proceed("Provide values for non-required parameters") {
 		for _, prop := range params {
-			c.addValueFor(prop)
+			if err := c.addValueFor(prop); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -135,12 +145,7 @@ func (p parameterInfo) AsValidatable() validation.Validatable {
 }
 
 func (c *CapabilityCreateOptions) getCategories() []string {
-	result := make([]string, 0, len(categories))
-	for k := range categories {
-		result = append(result, k)
-	}
-	sort.Strings(result)
-	return result
+	return c.catalog.Categories()
 }
 
 func (c *CapabilityCreateOptions) isValidCategory() bool {
@@ -148,13 +153,7 @@ func (c *CapabilityCreateOptions) isValidCategory() bool {
 }
 
 func (c *CapabilityCreateOptions) getTypesFor(category string) []string {
-	types := categories[category]
-	result := make([]string, 0, len(types))
-	for k := range types {
-		result = append(result, k)
-	}
-	sort.Strings(result)
-	return result
+	return c.catalog.TypesFor(category)
 }
 
 func (c *CapabilityCreateOptions) isValidTypeGivenCategory() bool {
@@ -166,7 +165,7 @@ func (c *CapabilityCreateOptions) isValidTypeFor(category string) bool {
 }
 
 func (c *CapabilityCreateOptions) getVersionsFor(category, subCategory string) []string {
-	return categories[category][subCategory]
+	return c.catalog.VersionsFor(category, subCategory)
 }
 
 func (c *CapabilityCreateOptions) isValidVersionFor(category, subCategory string) bool {
@@ -224,7 +223,16 @@ func (c *CapabilityCreateOptions) getParameterInfos() []parameterInfo {
 	return infos
 }
 
-func (c *CapabilityCreateOptions) addValueFor(prop parameterInfo) {
+func (c *CapabilityCreateOptions) hasParameter(name string) bool {
+	for _, parameter := range c.parameters {
+		if parameter.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *CapabilityCreateOptions) addValueFor(prop parameterInfo) error {
 	// first look if we have provided a value for this already
 	provided := ""
 	for _, parameter := range c.parameters {
@@ -232,45 +240,25 @@ func (c *CapabilityCreateOptions) addValueFor(prop parameterInfo) {
 			provided = parameter.Value
 		}
 	}
-	result := ui.Ask(fmt.Sprintf("Value for %s property %s:", prop.Type, prop.name), provided)
+	result, err := ui.Ask(fmt.Sprintf("Value for %s property %s:", prop.Type, prop.name), provided)
+	if err != nil {
+		return err
+	}
 	if result != provided {
 		c.parameters = append(c.parameters, halkyon.NameValuePair{
 			Name:  prop.name,
 			Value: result,
 		})
 	}
+	return nil
 }
 
-func getCapabilityInfos() chan categoryRegisty {
-	r := make(chan categoryRegisty)
-
-	go func() {
-		list, err := k8s.GetClient().HalkyonCapabilityInfoClient.CapabilityInfos().List(v1.ListOptions{})
-		if err != nil {
-			panic(err)
-		}
-
-		capInfos := make(categoryRegisty, 11)
-		for _, item := range list.Items {
-			category := item.Spec.Category
-			types, ok := capInfos[category]
-			if !ok {
-				types = make(typeRegistry, 7)
-				capInfos[category] = types
-			}
-
-			_, ok = types[item.Spec.Type]
-			if !ok {
-				types[item.Spec.Type] = strings.Split(item.Spec.Versions, v1beta12.CapabilityInfoVersionSeparator)
-			} else {
-				panic(fmt.Errorf("a type named %s is already registered for category %s", item.Spec.Type, category))
-			}
-		}
-
-		r <- capInfos
-	}()
-
-	return r
+func (c *CapabilityCreateOptions) completeWith(candidates func(c *catalog.Catalog) []string) []string {
+	cat, err := catalog.Get()
+	if err != nil {
+		return nil
+	}
+	return candidates(cat)
 }
 
 func NewCmdCreate(parent string) *cobra.Command {
@@ -286,5 +274,16 @@ func NewCmdCreate(parent string) *cobra.Command {
 	capability.Flags().StringVarP(&o.version, "version", "v", "", "Capability version")
 	capability.Flags().StringSliceVarP(&o.paramPairs, "parameters", "p", []string{}, "Capability-specific parameters")
 
+	// complete values based on the previously provided ones
+	cmdutil.RegisterFlagCompletion(capability, "category", func(string) []string {
+		return o.completeWith(func(c *catalog.Catalog) []string { return c.Categories() })
+	})
+	cmdutil.RegisterFlagCompletion(capability, "type", func(string) []string {
+		return o.completeWith(func(c *catalog.Catalog) []string { return c.TypesFor(o.category) })
+	})
+	cmdutil.RegisterFlagCompletion(capability, "version", func(string) []string {
+		return o.completeWith(func(c *catalog.Catalog) []string { return c.VersionsFor(o.category, o.subCategory) })
+	})
+
 	return capability
 }
@@ -1,11 +1,62 @@
 package capability
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
+	component "halkyon.io/api/component/v1beta1"
 	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/log"
+	"sort"
+	"strings"
 )
 
+type deleter struct {
+	force bool
+}
+
+// Plan refuses to delete capabilities that components are still bound to, unless forced
+func (d *deleter) Plan(names []string) ([]cmdutil.PlannedDeletion, error) {
+	deleted := make(map[string]bool, len(names))
+	for _, name := range names {
+		deleted[name] = true
+	}
+	components, err := cmdutil.EntityFor(cmdutil.Component).List()
+	if err != nil {
+		return nil, err
+	}
+	boundBy := make(map[string][]string, len(names))
+	for _, object := range components {
+		c := object.(*component.Component)
+		for _, required := range c.Spec.Capabilities.Requires {
+			bound := boundBy[required.BoundTo]
+			if deleted[required.BoundTo] && (len(bound) == 0 || bound[len(bound)-1] != c.Name) {
+				boundBy[required.BoundTo] = append(bound, c.Name)
+			}
+		}
+	}
+	if len(boundBy) == 0 {
+		return nil, nil
+	}
+
+	bindings := make([]string, 0, len(boundBy))
+	for name, components := range boundBy {
+		bindings = append(bindings, fmt.Sprintf("'%s' capability is bound by %s", name, strings.Join(components, ", ")))
+	}
+	sort.Strings(bindings)
+	if !d.force {
+		return nil, fmt.Errorf("%s, unbind them first or use --force", strings.Join(bindings, "; "))
+	}
+	for _, binding := range bindings {
+		log.Errorf("%s which will need to be bound to another capability", binding)
+	}
+	return nil, nil
+}
+
 func NewCmdDelete(fullParentName string) *cobra.Command {
+	d := &deleter{}
 	generic := cmdutil.NewDeleteOptions("capability", Entity)
-	return cmdutil.NewGenericDelete(fullParentName, generic)
+	generic.Delegate = d
+	cmd := cmdutil.NewGenericDelete(fullParentName, generic)
+	cmd.Flags().BoolVar(&d.force, "force", false, "Delete capabilities even if components are still bound to them")
+	return cmd
 }
@@ -7,19 +7,32 @@ import (
 	"halkyon.io/hal/pkg/cmdutil"
 	"halkyon.io/hal/pkg/k8s"
 	"halkyon.io/hal/pkg/ui"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-type client struct {
-	client v1beta1.CapabilityInterface
-	ns     string
+// client lazily connects to the cluster so that commands not needing it can run without one
+type client struct{}
+
+func (lc client) capabilities() v1beta1.CapabilityInterface {
+	c := k8s.GetClient()
+	return c.HalkyonCapabilityClient.Capabilities(c.Namespace)
 }
 
 var _ cmdutil.HalkyonEntity = &client{}
 
 func (lc client) Create(toCreate runtime.Object) error {
-	_, err := lc.client.Create(toCreate.(*v1beta12.Capability))
+	capability := toCreate.(*v1beta12.Capability)
+	c, err := lc.GetTyped(capability.Name)
+	if errors.IsNotFound(err) {
+		// create
+		_, err = lc.capabilities().Create(capability)
+	} else if err == nil {
+		capability.ResourceVersion = c.ResourceVersion
+		_, err = lc.capabilities().Update(capability)
+	}
+
 	return err
 }
 
@@ -28,7 +41,7 @@ func (lc client) Get(name string) (runtime.Object, error) {
 }
 
 func (lc client) GetTyped(name string) (*v1beta12.Capability, error) {
-	return lc.client.Get(name, v1.GetOptions{})
+	return lc.capabilities().Get(name, v1.GetOptions{})
 }
 
 func (lc client) GetKnown() ui.DisplayableMap {
@@ -69,7 +82,7 @@ func (lc client) GetMatching(spec ...v1beta12.CapabilitySpec) ui.DisplayableMap
 	r := make(chan ui.DisplayableMap)
 
 	go func() {
-		list, err := lc.client.List(v1.ListOptions{})
+		list, err := lc.capabilities().List(v1.ListOptions{})
 		if err != nil {
 			r <- ui.Empty
 			return
@@ -88,15 +101,39 @@ func (lc client) GetMatching(spec ...v1beta12.CapabilitySpec) ui.DisplayableMap
 	return <-r
 }
 
+func (lc client) List() ([]runtime.Object, error) {
+	list, err := lc.capabilities().List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]runtime.Object, 0, len(list.Items))
+	for i := range list.Items {
+		item := list.Items[i]
+		item.TypeMeta = TypeMeta()
+		result = append(result, &item)
+	}
+	return result, nil
+}
+
 func (lc client) Delete(name string, options *v1.DeleteOptions) error {
-	return lc.client.Delete(name, options)
+	return lc.capabilities().Delete(name, options)
 }
 
 func (lc client) GetNamespace() string {
-	return lc.ns
+	return k8s.GetClient().Namespace
+}
+
+// New creates a capability with the specified name and spec, ready to be created on the cluster
+func New(name string, spec v1beta12.CapabilitySpec) *v1beta12.Capability {
+	return &v1beta12.Capability{
+		TypeMeta:   TypeMeta(),
+		ObjectMeta: v1.ObjectMeta{Name: name},
+		Spec:       spec,
+	}
 }
 
-func typeMeta() v1.TypeMeta {
+// TypeMeta returns the type information of halkyon capabilities
+func TypeMeta() v1.TypeMeta {
 	return v1.TypeMeta{
 		Kind:       v1beta12.Kind,
 		APIVersion: "halkyon.io/v1beta1",
@@ -106,9 +143,5 @@ func typeMeta() v1.TypeMeta {
 var Entity client
 
 func init() {
-	c := k8s.GetClient()
-	Entity = client{
-		client: c.HalkyonCapabilityClient.Capabilities(c.Namespace),
-		ns:     c.Namespace,
-	}
+	cmdutil.RegisterEntity(cmdutil.Capability, Entity)
 }
@@ -0,0 +1,37 @@
+package capability
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/api/capability/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type lister struct{}
+
+var _ cmdutil.Lister = lister{}
+
+func (lister) Headers() []string {
+	return []string{"name", "category", "type", "version", "status"}
+}
+
+func (lister) Row(object runtime.Object) []string {
+	c := object.(*v1beta1.Capability)
+	status := fmt.Sprintf("%v", c.Status.Reason)
+	if len(status) == 0 {
+		status = "<none>"
+	}
+	return []string{
+		c.Name,
+		fmt.Sprintf("%v", c.Spec.Category),
+		fmt.Sprintf("%v", c.Spec.Type),
+		c.Spec.Version,
+		status,
+	}
+}
+
+func NewCmdList(fullParentName string) *cobra.Command {
+	generic := cmdutil.NewListOptions(cmdutil.Capability, Entity, lister{})
+	return cmdutil.NewGenericList(fullParentName, generic)
+}
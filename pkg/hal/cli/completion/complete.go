@@ -0,0 +1,117 @@
+package completion
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"halkyon.io/hal/pkg/cmdutil"
+	"sort"
+	"strings"
+)
+
+// complete computes the candidates for the partial word to complete given the words preceding it on the command line
+func complete(root *cobra.Command, words []string, toComplete string) []string {
+	cmd, remaining, err := root.Find(words)
+	if err != nil {
+		return nil
+	}
+	// make sure inherited persistent flags are also known to the command's flag set
+	_ = cmd.InheritedFlags()
+
+	// check whether we're completing a flag value, either provided as --flag=value or as the word following the flag
+	var flag *pflag.Flag
+	prefix := ""
+	if strings.HasPrefix(toComplete, "-") {
+		if i := strings.Index(toComplete, "="); i > 0 {
+			flag = lookupFlag(cmd, toComplete[:i])
+			prefix = toComplete[:i+1]
+			toComplete = toComplete[i+1:]
+		}
+	} else if len(remaining) > 0 {
+		last := remaining[len(remaining)-1]
+		if strings.HasPrefix(last, "-") && !strings.Contains(last, "=") {
+			// flags not requiring a value, e.g. boolean ones, cannot be followed by their value
+			if flag = lookupFlag(cmd, last); flag != nil && len(flag.NoOptDefVal) == 0 {
+				remaining = remaining[:len(remaining)-1]
+			} else {
+				flag = nil
+			}
+		}
+	}
+
+	// parse already provided flags so that completion functions can use their values
+	_ = cmd.ParseFlags(remaining)
+
+	if flag != nil {
+		if strings.HasSuffix(flag.Value.Type(), "Slice") {
+			// only complete the last of comma-separated values
+			if i := strings.LastIndex(toComplete, ","); i >= 0 {
+				prefix += toComplete[:i+1]
+				toComplete = toComplete[i+1:]
+			}
+		}
+		fn, ok := cmdutil.FlagCompletion(flag)
+		if !ok {
+			return nil
+		}
+		return filter(fn(toComplete), prefix, toComplete)
+	}
+
+	if strings.HasPrefix(toComplete, "-") {
+		return filter(flagNames(cmd), "", toComplete)
+	}
+
+	if cmd.HasAvailableSubCommands() {
+		names := make([]string, 0, len(cmd.Commands()))
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				names = append(names, sub.Name())
+			}
+		}
+		return filter(names, "", toComplete)
+	}
+
+	// only complete positional arguments if the command accepts one more
+	args := cmd.Flags().Args()
+	if cmd.Args != nil && cmd.Args(cmd, append(args, toComplete)) != nil {
+		return nil
+	}
+	fn, ok := cmdutil.ArgsCompletion(cmd)
+	if !ok {
+		return nil
+	}
+	return filter(fn(toComplete), "", toComplete)
+}
+
+func lookupFlag(cmd *cobra.Command, word string) *pflag.Flag {
+	if strings.HasPrefix(word, "--") {
+		return cmd.Flags().Lookup(strings.TrimPrefix(word, "--"))
+	}
+	// a group of shorthands, e.g. -fc, can only be followed by the value of the last one
+	shorthands := strings.TrimPrefix(word, "-")
+	if len(shorthands) == 0 {
+		return nil
+	}
+	return cmd.Flags().ShorthandLookup(shorthands[len(shorthands)-1:])
+}
+
+func flagNames(cmd *cobra.Command) []string {
+	names := make([]string, 0, 17)
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		if !flag.Hidden {
+			names = append(names, "--"+flag.Name)
+		}
+	})
+	return names
+}
+
+// filter only keeps the candidates starting with the partial word being completed, prepending the specified prefix
+func filter(candidates []string, prefix, toComplete string) []string {
+	result := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, toComplete) {
+			result = append(result, prefix+candidate)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
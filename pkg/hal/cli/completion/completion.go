@@ -0,0 +1,94 @@
+package completion
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/log"
+	"halkyon.io/hal/pkg/validation"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"os"
+)
+
+const (
+	commandName = "completion"
+	// completeCommandName is the hidden command called by the shell scripts to retrieve candidates
+	completeCommandName = "__complete"
+)
+
+type options struct {
+	shell validation.EnumValue
+	root  *cobra.Command
+}
+
+var (
+	completionExample = ktemplates.Examples(`  # Enable completion in the current bash session
+  source <(%[1]s bash)
+
+  # Enable completion for all zsh sessions
+  %[1]s zsh > "${fpath[1]}/_hal"
+
+  # Enable completion for all fish sessions
+  %[1]s fish > ~/.config/fish/completions/hal.fish`)
+)
+
+func (o *options) Complete(name string, cmd *cobra.Command, args []string) error {
+	o.shell.Provided = args[0]
+	o.root = cmd.Root()
+	return nil
+}
+
+func (o *options) Validate() error {
+	return o.shell.Contains(o.shell.Provided)
+}
+
+func (o *options) Run() error {
+	var script string
+	switch o.shell.Provided {
+	case "bash":
+		script = bashScript
+	case "zsh":
+		script = zshScript
+	case "fish":
+		script = fishScript
+	}
+	_, err := fmt.Fprintf(os.Stdout, script, o.root.Name())
+	return err
+}
+
+func NewCmdCompletion(parent string) *cobra.Command {
+	o := &options{
+		shell: validation.NewEnumValue("shell", "bash", "zsh", "fish"),
+	}
+	completion := &cobra.Command{
+		Use:     fmt.Sprintf("%s bash|zsh|fish", commandName),
+		Short:   "Output the shell completion script for the specified shell",
+		Long:    `Output the shell completion script for the specified shell. Besides commands and flags, runtimes, capabilities, components and other known values are completed using the cluster's current state.`,
+		Example: fmt.Sprintf(completionExample, cmdutil.CommandName(commandName, parent)),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.GenericRun(o, cmd, args)
+		},
+	}
+	cmdutil.RegisterArgsCompletion(completion, cmdutil.EnumCompletion(o.shell))
+	return completion
+}
+
+// NewCmdComplete creates the hidden command outputting, one per line, the candidates for the last provided argument
+func NewCmdComplete() *cobra.Command {
+	return &cobra.Command{
+		Use:                completeCommandName,
+		Hidden:             true,
+		DisableFlagParsing: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			// only candidates must be output
+			log.Silence()
+			if len(args) == 0 {
+				args = []string{""}
+			}
+			for _, candidate := range complete(cmd.Root(), args[:len(args)-1], args[len(args)-1]) {
+				fmt.Fprintln(os.Stdout, candidate)
+			}
+		},
+	}
+}
@@ -0,0 +1,49 @@
+package completion
+
+// the scripts all delegate to the hidden __complete command, passing it the words preceding the one being completed
+// followed by the partial word itself. %[1]s is replaced by the name of the root command.
+
+const bashScript = `# bash completion for %[1]s
+_%[1]s_complete() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local -a words
+    read -ra words <<< "${line}"
+    # an empty word needs to be completed if the line ends with a space
+    if [[ "${line}" == *" " ]]; then
+        words+=("")
+    fi
+
+    local IFS=$'\n'
+    COMPREPLY=($("${words[0]}" __complete "${words[@]:1}" 2>/dev/null))
+
+    # bash considers '=' as a word separator so only the value part of --flag=value candidates must be kept
+    local current="${words[${#words[@]}-1]}"
+    if [[ "${current}" == -*=* && "${COMP_WORDBREAKS}" == *"="* ]]; then
+        COMPREPLY=("${COMPREPLY[@]#*=}")
+    fi
+}
+complete -o default -F _%[1]s_complete %[1]s
+`
+
+const zshScript = `#compdef %[1]s
+# zsh completion for %[1]s
+_%[1]s() {
+    local -a candidates
+    candidates=(${(f)"$(${words[1]} __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+    if (( ${#candidates} )); then
+        compadd -Q -- "${candidates[@]}"
+    else
+        _files
+    fi
+}
+compdef _%[1]s %[1]s
+`
+
+const fishScript = `# fish completion for %[1]s
+function __%[1]s_complete
+    set -l words (commandline -opc)
+    set -e words[1]
+    %[1]s __complete $words (commandline -ct) 2>/dev/null
+end
+complete -c %[1]s -f -a '(__%[1]s_complete)'
+`
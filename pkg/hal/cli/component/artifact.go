@@ -0,0 +1,195 @@
+package component
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// buildDirs lists the directories, relative to the component's, in which Maven and Gradle output artifacts
+var buildDirs = []string{"target", "build", filepath.Join("build", "libs")}
+
+// artifact is a runnable result of packaging a component, pushed in binary mode
+type artifact struct {
+	// path of the artifact, which is a directory for layouts spanning several files such as Quarkus' fast-jar
+	path string
+	// kind describes the artifact for display purposes
+	kind string
+}
+
+func (a artifact) String() string {
+	return fmt.Sprintf("%s %s", a.kind, a.path)
+}
+
+// artifactFinder looks for artifacts of a given kind in a component's directory, returning the paths of the candidates
+type artifactFinder struct {
+	kind string
+	find func(componentDir string) []string
+}
+
+// artifactFinders lists the known finders in decreasing order of precedence: the first one finding candidates decides
+// which artifact is pushed, thin jars produced alongside Quarkus or native builds being ignored that way
+var artifactFinders = []artifactFinder{
+	{kind: "Quarkus fast-jar", find: findQuarkusApps},
+	{kind: "native executable", find: findNativeExecutables},
+	{kind: "jar", find: archiveFinder(".jar")},
+	{kind: "war", find: archiveFinder(".war")},
+}
+
+// ignoredJarAffixes lists prefixes and suffixes of jars that are produced alongside runnable ones but cannot be run
+var (
+	ignoredJarPrefixes = []string{"original-"}
+	ignoredJarSuffixes = []string{"-sources.jar", "-javadoc.jar", "-tests.jar", "-test-sources.jar", "-plain.jar"}
+)
+
+// findArtifact looks for the runnable artifact of the component in the specified directory
+func findArtifact(componentDir string) (artifact, error) {
+	for _, finder := range artifactFinders {
+		candidates := finder.find(componentDir)
+		switch len(candidates) {
+		case 0:
+			continue
+		case 1:
+			return artifact{path: candidates[0], kind: finder.kind}, nil
+		default:
+			for i, candidate := range candidates {
+				candidates[i], _ = filepath.Rel(componentDir, candidate)
+			}
+			return artifact{}, fmt.Errorf("found several %s artifacts in %s: %s, use --artifact to select the one to push",
+				finder.kind, componentDir, strings.Join(candidates, ", "))
+		}
+	}
+	return artifact{}, fmt.Errorf("no runnable artifact found in %s, package the component first or use --artifact to select the one to push", componentDir)
+}
+
+// artifactAt describes the artifact explicitly provided at the specified path
+func artifactAt(path string) (artifact, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return artifact{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return artifact{}, fmt.Errorf("invalid artifact: %v", err)
+	}
+	kind := "artifact"
+	if info.IsDir() {
+		kind = "artifact directory"
+	}
+	return artifact{path: path, kind: kind}, nil
+}
+
+func findQuarkusApps(componentDir string) []string {
+	var found []string
+	for _, dir := range []string{"target", "build"} {
+		app := filepath.Join(componentDir, dir, "quarkus-app")
+		if info, err := os.Stat(filepath.Join(app, "quarkus-run.jar")); err == nil && info.Mode().IsRegular() {
+			found = append(found, app)
+		}
+	}
+	return found
+}
+
+func findNativeExecutables(componentDir string) []string {
+	return findInBuildDirs(componentDir, func(name string, info os.FileInfo) bool {
+		return strings.HasSuffix(name, "-runner") && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+	})
+}
+
+// archiveFinder finds archives with the specified extension, favoring Quarkus' legacy runner jars over the thin jars
+// produced alongside them
+func archiveFinder(extension string) func(componentDir string) []string {
+	return func(componentDir string) []string {
+		found := findInBuildDirs(componentDir, func(name string, info os.FileInfo) bool {
+			return strings.HasSuffix(name, extension) && info.Mode().IsRegular() && !isIgnoredJar(name)
+		})
+		var runners []string
+		for _, path := range found {
+			if strings.HasSuffix(path, "-runner"+extension) {
+				runners = append(runners, path)
+			}
+		}
+		if len(runners) > 0 {
+			return runners
+		}
+		return found
+	}
+}
+
+func isIgnoredJar(name string) bool {
+	for _, prefix := range ignoredJarPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	for _, suffix := range ignoredJarSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// findInBuildDirs returns the sorted paths of the files directly contained in build directories accepted by the
+// specified function
+func findInBuildDirs(componentDir string, accept func(name string, info os.FileInfo) bool) []string {
+	var found []string
+	for _, dir := range buildDirs {
+		dir = filepath.Join(componentDir, dir)
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			// build directory doesn't exist or isn't readable, skip it
+			continue
+		}
+		for _, file := range files {
+			if accept(file.Name(), file) {
+				found = append(found, filepath.Join(dir, file.Name()))
+			}
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// walk calls the specified function for each regular file of the artifact, providing its path relative to the
+// artifact's parent directory
+func (a artifact) walk(fn func(path, relative string, info os.FileInfo) error) error {
+	parent := filepath.Dir(a.path)
+	return filepath.Walk(a.path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relative, err := filepath.Rel(parent, path)
+		if err != nil {
+			return err
+		}
+		return fn(path, filepath.ToSlash(relative), info)
+	})
+}
+
+// digest computes a digest of the artifact's content and layout
+func (a artifact) digest() (string, error) {
+	hash := sha1.New()
+	err := a.walk(func(path, relative string, info os.FileInfo) error {
+		_, _ = fmt.Fprintln(hash, relative)
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(hash, file)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
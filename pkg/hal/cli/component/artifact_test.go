@@ -0,0 +1,91 @@
+package component
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindArtifact(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]os.FileMode
+		expected string
+		error    string
+	}{
+		{
+			name:     "maven jar ignoring attached artifacts",
+			files:    map[string]os.FileMode{"target/app-sources.jar": 0644, "target/original-app.jar": 0644, "target/app.jar": 0644, "target/app-javadoc.jar": 0644},
+			expected: "target/app.jar",
+		},
+		{
+			name:     "gradle jar",
+			files:    map[string]os.FileMode{"build/libs/app.jar": 0644, "build/libs/app-plain.jar": 0644},
+			expected: "build/libs/app.jar",
+		},
+		{
+			name:     "quarkus fast-jar over thin jar",
+			files:    map[string]os.FileMode{"target/app.jar": 0644, "target/quarkus-app/quarkus-run.jar": 0644, "target/quarkus-app/lib/dep.jar": 0644},
+			expected: "target/quarkus-app",
+		},
+		{
+			name:     "quarkus legacy runner jar",
+			files:    map[string]os.FileMode{"target/app.jar": 0644, "target/app-runner.jar": 0644},
+			expected: "target/app-runner.jar",
+		},
+		{
+			name:     "native executable",
+			files:    map[string]os.FileMode{"target/app.jar": 0644, "target/app-runner": 0755},
+			expected: "target/app-runner",
+		},
+		{
+			name:     "war",
+			files:    map[string]os.FileMode{"target/app.war": 0644},
+			expected: "target/app.war",
+		},
+		{
+			name:  "ambiguous jars",
+			files: map[string]os.FileMode{"target/b.jar": 0644, "target/a.jar": 0644},
+			error: "found several jar artifacts",
+		},
+		{
+			name:  "nothing packaged",
+			files: map[string]os.FileMode{"src/main/java/App.java": 0644},
+			error: "no runnable artifact found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hal-artifact")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			for name, mode := range tt.files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := ioutil.WriteFile(path, []byte(name), mode); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			found, err := findArtifact(dir)
+			if len(tt.error) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.error) {
+					t.Errorf("expected error containing %q, got %v", tt.error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := filepath.Join(dir, tt.expected); found.path != expected {
+				t.Errorf("expected %s, got %s", expected, found.path)
+			}
+		})
+	}
+}
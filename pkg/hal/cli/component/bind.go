@@ -3,81 +3,420 @@ package component
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	v1beta12 "halkyon.io/api/capability/v1beta1"
 	"halkyon.io/api/component/v1beta1"
+	halkyon "halkyon.io/api/v1beta1"
 	"halkyon.io/hal/pkg/cmdutil"
 	"halkyon.io/hal/pkg/hal/cli/capability"
 	"halkyon.io/hal/pkg/log"
 	"halkyon.io/hal/pkg/ui"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"sort"
+	"strings"
 )
 
-const bindCommandName = "bind"
+const (
+	bindCommandName   = "bind"
+	unbindCommandName = "unbind"
+)
 
 type bindOptions struct {
-	component *v1beta1.Component
 	*cmdutil.ComponentTargetingOptions
-	capability string
+	requirement string
+	to          string
+	unbind      bool
+	auto        bool
+	// targets records the changes to make to each targeted component, keyed by component name
+	targets map[string]*bindTarget
 }
 
+// bindTarget records the changes to make to one of the targeted components
+type bindTarget struct {
+	component *v1beta1.Component
+	// bindings records the new binding of each changed requirement, an empty value meaning that it was unbound
+	bindings map[string]string
+	// created records the capabilities to create so that automatically resolved requirements can be bound to them
+	created []*v1beta12.Capability
+}
+
+var (
+	bindExample = ktemplates.Examples(`  # Select which capabilities the requirements of the current component are bound to
+  %[1]s
+
+  # Bind the db requirement of the backend-sb component to the postgres-prod capability
+  %[1]s -c backend-sb --requirement db --to postgres-prod
+
+  # Bind all unbound requirements of the current component, creating the missing capabilities
+  %[1]s --auto -y`)
+	unbindExample = ktemplates.Examples(`  # Unbind the db requirement of the backend-sb component
+  %[1]s -c backend-sb --requirement db`)
+)
+
 func (o *bindOptions) SetTargetingOptions(options *cmdutil.ComponentTargetingOptions) {
 	o.ComponentTargetingOptions = options
 }
 
 func (o *bindOptions) Complete(name string, cmd *cobra.Command, args []string) (err error) {
+	if o.auto && len(o.to) > 0 {
+		return fmt.Errorf("--auto and --to cannot be used together")
+	}
 	// get the targeted component
-	o.component, err = Entity.GetTyped(o.GetTargetedComponentName())
+	c, err := Entity.GetTyped(o.GetTargetedComponentName())
 	if err != nil {
 		return err
 	}
+	if o.targets == nil {
+		o.targets = make(map[string]*bindTarget, 7)
+	}
+	t := &bindTarget{component: c, bindings: make(map[string]string, len(c.Spec.Capabilities.Requires))}
+	o.targets[o.GetTargetedComponentName()] = t
 
-	// get list of required capabilities and check if they are already bound
-	for i, required := range o.component.Spec.Capabilities.Requires {
-		// filter capabilities that don't match the requirements
-		matching := capability.Entity.GetMatching(required.Spec)
+	indices, err := o.selectRequirements(cmd, c)
+	if err != nil {
+		return err
+	}
+	for _, i := range indices {
+		required := &c.Spec.Capabilities.Requires[i]
+		if o.unbind {
+			if len(required.BoundTo) == 0 {
+				ui.OutputMessage(fmt.Sprintf("'%s' requirement of '%s' component isn't bound", required.Name, c.Name))
+				continue
+			}
+			required.BoundTo = ""
+			t.bindings[required.Name] = ""
+			continue
+		}
 
-		// only consider unbound capabilities for now
-		isBound := len(required.BoundTo) > 0
-		if isBound {
-			specified, found := matching.GetByName(required.BoundTo)
-			if found {
-				ui.OutputSelection("Already bound capability", specified.Display())
-			} else {
-				ui.OutputError(fmt.Sprintf("No capability matching %v named %s was found", required.Spec, required.BoundTo))
+		var boundTo string
+		if o.auto {
+			if len(required.BoundTo) > 0 {
+				log.Infof("'%s' requirement of '%s' component is already bound to '%s' capability", required.Name, c.Name, required.BoundTo)
+				continue
 			}
+			boundTo, err = o.resolveCapability(t, *required)
+		} else {
+			boundTo, err = o.selectCapability(*required)
+		}
+		if err != nil {
+			return err
 		}
-		if !isBound || ui.Proceed("Change bound capability") {
-			// ask user to select which matching capability to bind
-			selected := ui.SelectDisplayable("Matching capability", matching)
-			updated := required.DeepCopy()
-			o.capability = selected.Name()
-			updated.BoundTo = o.capability
-			o.component.Spec.Capabilities.Requires[i] = *updated
+		if len(boundTo) > 0 && boundTo != required.BoundTo {
+			required.BoundTo = boundTo
+			t.bindings[required.Name] = boundTo
 		}
 	}
 
 	return nil
 }
 
+// selectRequirements returns the indices of the requirements of the specified component to process: the specified one,
+// all of them when automatically or interactively binding, or the only one the component has
+func (o *bindOptions) selectRequirements(cmd *cobra.Command, c *v1beta1.Component) ([]int, error) {
+	requires := c.Spec.Capabilities.Requires
+	if len(requires) == 0 {
+		return nil, fmt.Errorf("'%s' component doesn't require any capability", c.Name)
+	}
+	names := make([]string, 0, len(requires))
+	for i, required := range requires {
+		if required.Name == o.requirement {
+			return []int{i}, nil
+		}
+		names = append(names, required.Name)
+	}
+	if len(o.requirement) > 0 {
+		return nil, fmt.Errorf("'%s' component has no '%s' requirement, known requirements: %s", c.Name, o.requirement, strings.Join(names, ", "))
+	}
+
+	switch {
+	case len(requires) == 1:
+		return []int{0}, nil
+	case o.auto:
+		return allIndices(len(requires)), nil
+	case !cmdutil.IsInteractive(cmd):
+		return nil, fmt.Errorf("'%s' component has several requirements, use --requirement to select one of: %s", c.Name, strings.Join(names, ", "))
+	case o.unbind || len(o.to) > 0:
+		if len(o.Targets()) > 1 {
+			ui.OutputMessage(fmt.Sprintf("Selecting requirement of '%s' component", c.Name))
+		}
+		selected, err := ui.Select("Requirement", names)
+		if err != nil {
+			return nil, err
+		}
+		for i, name := range names {
+			if name == selected {
+				return []int{i}, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown requirement %s", selected)
+	default:
+		return allIndices(len(requires)), nil
+	}
+}
+
+func allIndices(length int) []int {
+	indices := make([]int, 0, length)
+	for i := 0; i < length; i++ {
+		indices = append(indices, i)
+	}
+	return indices
+}
+
+// selectCapability determines which capability the specified requirement should be bound to, either validating the one
+// specified using --to or asking the user to select one of the matching capabilities, returning an empty name if the
+// binding is to be left unchanged
+func (o *bindOptions) selectCapability(required v1beta1.RequiredCapabilityConfig) (string, error) {
+	if len(o.to) > 0 {
+		target, err := capability.Entity.GetTyped(o.to)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return "", fmt.Errorf("no capability named '%s' exists", o.to)
+			}
+			return "", err
+		}
+		if !target.Spec.Matches(required.Spec) {
+			return "", fmt.Errorf("'%s' capability (%s) doesn't match '%s' requirement (%s)", o.to,
+				capability.GetDisplay(target.Name, target.Spec), required.Name, capability.GetDisplay(required.Name, required.Spec))
+		}
+		return o.to, nil
+	}
+
+	// filter capabilities that don't match the requirements
+	matching := capability.Entity.GetMatching(required.Spec)
+	if matching.Len() == 0 {
+		return "", fmt.Errorf("no capability matches '%s' requirement (%s), create one first or use --auto", required.Name, capability.GetDisplay(required.Name, required.Spec))
+	}
+
+	if len(required.BoundTo) > 0 {
+		specified, found := matching.GetByName(required.BoundTo)
+		if found {
+			ui.OutputSelection("Already bound capability", specified.Display())
+		} else {
+			ui.OutputError(fmt.Sprintf("No capability matching %v named %s was found", required.Spec, required.BoundTo))
+		}
+		// the current binding is kept when running non-interactively
+		if !ui.Proceed("Change bound capability") {
+			return "", nil
+		}
+	}
+	// ask user to select which matching capability to bind
+	selected, err := ui.SelectDisplayable("Matching capability", matching)
+	if err != nil {
+		return "", err
+	}
+	return selected.Name(), nil
+}
+
+// resolveCapability determines which capability the specified unbound requirement of the specified target should be
+// automatically bound to: the only matching one, the preferred one if several match or a new capability created from
+// the requirement's spec if none does and the user agrees to it
+func (o *bindOptions) resolveCapability(t *bindTarget, required v1beta1.RequiredCapabilityConfig) (string, error) {
+	matching := capability.Entity.GetMatching(required.Spec)
+	candidates := make([]v1beta12.Capability, 0, matching.Len())
+	for i := 0; i < matching.Len(); i++ {
+		candidates = append(candidates, matching.GetByIndex(i).GetUnderlying().(v1beta12.Capability))
+	}
+	// capabilities created for previous requirements, possibly of other targets, are also candidates even though they
+	// don't exist yet
+	for _, c := range o.created() {
+		if c.Spec.Matches(required.Spec) {
+			candidates = append(candidates, *c)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return o.newCapabilityFor(t, required)
+	case 1:
+		log.Infof("Binding '%s' requirement to '%s', the only matching capability", required.Name, candidates[0].Name)
+		return candidates[0].Name, nil
+	default:
+		preferred := preferredCapability(required, candidates)
+		log.Infof("Binding '%s' requirement to '%s', preferred among %d matching capabilities", required.Name, preferred.Name, len(candidates))
+		return preferred.Name, nil
+	}
+}
+
+// preferredCapability deterministically picks one of several capabilities matching the specified requirement,
+// preferring ready capabilities, then the ones with the exact requested version, then the one named after the
+// requirement, ties being broken using the capabilities' names
+func preferredCapability(required v1beta1.RequiredCapabilityConfig, candidates []v1beta12.Capability) v1beta12.Capability {
+	criteria := func(c v1beta12.Capability) []bool {
+		return []bool{
+			c.Status.Reason == halkyon.ReasonReady,
+			c.Spec.Version == required.Spec.Version,
+			c.Name == required.Name,
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		ci, cj := criteria(candidates[i]), criteria(candidates[j])
+		for k := range ci {
+			if ci[k] != cj[k] {
+				return ci[k]
+			}
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	return candidates[0]
+}
+
+// newCapabilityFor offers to create a capability from the spec and parameters of the specified requirement of the
+// specified target, named after it if that name is available, returning the name of the capability to bind to
+func (o *bindOptions) newCapabilityFor(t *bindTarget, required v1beta1.RequiredCapabilityConfig) (string, error) {
+	display := capability.GetDisplay(required.Name, required.Spec)
+	name := required.Name
+	if taken, err := o.isCapabilityNameTaken(name); err != nil {
+		return "", err
+	} else if taken {
+		name = t.component.Name + "-" + required.Name
+		if taken, err = o.isCapabilityNameTaken(name); err != nil {
+			return "", err
+		} else if taken {
+			return "", fmt.Errorf("no capability matches '%s' requirement (%s) and '%s' capability already exists", required.Name, display, name)
+		}
+	}
+
+	if confirmed, err := ui.Confirm(fmt.Sprintf("No capability matches '%s' requirement (%s), create '%s' capability", required.Name, display, name)); err != nil {
+		return "", err
+	} else if !confirmed {
+		return "", fmt.Errorf("no capability matches '%s' requirement (%s)", required.Name, display)
+	}
+	spec := required.Spec
+	spec.Parameters = append([]halkyon.NameValuePair(nil), required.Spec.Parameters...)
+	t.created = append(t.created, capability.New(name, spec))
+	return name, nil
+}
+
+// created returns the capabilities to create for all the targeted components
+func (o *bindOptions) created() []*v1beta12.Capability {
+	var created []*v1beta12.Capability
+	for _, t := range o.targets {
+		created = append(created, t.created...)
+	}
+	return created
+}
+
+func (o *bindOptions) isCapabilityNameTaken(name string) (bool, error) {
+	for _, c := range o.created() {
+		if c.Name == name {
+			return true, nil
+		}
+	}
+	_, err := capability.Entity.GetTyped(name)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
 func (o *bindOptions) Validate() error {
 	return nil
 }
 
 func (o *bindOptions) Run() error {
-	_, err := Entity.client.Update(o.component)
-	if err == nil {
-		log.Successf("Successfully bound '%s' capability to '%s' component", o.capability, o.component.Name)
+	t := o.targets[o.GetTargetedComponentName()]
+	if len(t.bindings) == 0 {
+		log.Infof("Bindings of '%s' component are unchanged", t.component.Name)
+		return nil
+	}
+	// create capabilities first so that the component's bindings can be resolved as soon as it's updated
+	created := make([]string, 0, len(t.created))
+	for _, c := range t.created {
+		if err := capability.Entity.Create(c); err != nil {
+			return withCreatedCapabilities(fmt.Errorf("couldn't create '%s' capability: %v", c.Name, err), created)
+		}
+		log.Successf("Created '%s' capability", c.Name)
+		created = append(created, c.Name)
+	}
+	if _, err := Entity.components().Update(t.component); err != nil {
+		return withCreatedCapabilities(err, created)
 	}
-	return err
+	for requirement, boundTo := range t.bindings {
+		if len(boundTo) > 0 {
+			log.Successf("Successfully bound '%s' requirement of '%s' component to '%s' capability", requirement, t.component.Name, boundTo)
+		} else {
+			log.Successf("Successfully unbound '%s' requirement of '%s' component", requirement, t.component.Name)
+		}
+	}
+	return o.updateLocalDescriptor(t)
+}
+
+// withCreatedCapabilities mentions the capabilities that were created for nothing when bindings couldn't be updated
+func withCreatedCapabilities(err error, created []string) error {
+	if len(created) == 0 {
+		return err
+	}
+	return fmt.Errorf("%v, the following capabilities were created but aren't bound and might need to be deleted: %s", err, strings.Join(created, ", "))
+}
+
+// updateLocalDescriptor records the changed bindings of the specified target in the halkyon descriptor defining the
+// component locally, if any
+func (o *bindOptions) updateLocalDescriptor(t *bindTarget) error {
+	local, ok := cmdutil.LoadAvailableHalkyonEntities(o.GetTargetedComponentPath()).GetDefinedEntitiesWith(cmdutil.Component)[t.component.Name]
+	if !ok {
+		return nil
+	}
+	if cmdutil.IsGeneratedDescriptor(local.Path) {
+		log.Infof("Bindings need to be updated in the sources generating %s", local.Path)
+		return nil
+	}
+	descriptor, err := cmdutil.LoadHalkyonDescriptor(local.Path)
+	if err != nil {
+		return err
+	}
+	c := descriptor.GetDefinedEntitiesWith(cmdutil.Component)[t.component.Name].Entity.(*v1beta1.Component)
+	for i, required := range c.Spec.Capabilities.Requires {
+		if boundTo, ok := t.bindings[required.Name]; ok {
+			c.Spec.Capabilities.Requires[i].BoundTo = boundTo
+		}
+	}
+	if err := descriptor.OutputAt(); err != nil {
+		return fmt.Errorf("couldn't update %s: %v", local.Path, err)
+	}
+	log.Infof("Updated %s", local.Path)
+	return nil
 }
 
 func NewCmdBind(fullParentName string) *cobra.Command {
 	o := &bindOptions{}
 	bind := &cobra.Command{
-		Use:     fmt.Sprintf("%s [flags]", bindCommandName),
-		Short:   "Bind the component to a capability",
-		Long:    `Bind the component to a capability.`,
-		Example: fmt.Sprintf(modeExample, cmdutil.CommandName(bindCommandName, fullParentName)),
+		Use:   fmt.Sprintf("%s [flags]", bindCommandName),
+		Short: "Bind the component to a capability",
+		Long: `Bind the component to a capability.
+
+With --auto, each unbound requirement is bound without asking: to the only matching capability, to the preferred one
+when several match (ready ones first, then the ones with the exact requested version, then the one named after the
+requirement, then in alphabetical order) or, when none matches, to a new capability created from the requirement's
+spec and parameters once confirmed.
+
+The local halkyon descriptor defining the component, if any, is updated as well.`,
+		Example: fmt.Sprintf(bindExample, cmdutil.CommandName(bindCommandName, fullParentName)),
 		Args:    cobra.NoArgs,
 	}
 	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, bind)
+	addRequirementFlag(bind, &o.requirement)
+	bind.Flags().StringVar(&o.to, "to", "", "Name of the capability to bind the requirement to")
+	cmdutil.RegisterFlagCompletion(bind, "to", cmdutil.EntityNamesCompletion(cmdutil.Capability))
+	bind.Flags().BoolVar(&o.auto, "auto", false, "Automatically bind unbound requirements, creating matching capabilities if needed")
 	return bind
 }
+
+func NewCmdUnbind(fullParentName string) *cobra.Command {
+	o := &bindOptions{unbind: true}
+	unbind := &cobra.Command{
+		Use:   fmt.Sprintf("%s [flags]", unbindCommandName),
+		Short: "Unbind the component from a capability",
+		Long: `Unbind a requirement of the component from the capability it's bound to.
+
+The local halkyon descriptor defining the component, if any, is updated as well.`,
+		Example: fmt.Sprintf(unbindExample, cmdutil.CommandName(unbindCommandName, fullParentName)),
+		Args:    cobra.NoArgs,
+	}
+	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, unbind)
+	addRequirementFlag(unbind, &o.requirement)
+	return unbind
+}
+
+func addRequirementFlag(cmd *cobra.Command, requirement *string) {
+	cmd.Flags().StringVar(requirement, "requirement", "", "Name of the requirement to process, can be omitted if the component has a single one")
+}
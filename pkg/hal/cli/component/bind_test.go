@@ -0,0 +1,61 @@
+package component
+
+import (
+	capability "halkyon.io/api/capability/v1beta1"
+	"halkyon.io/api/component/v1beta1"
+	halkyon "halkyon.io/api/v1beta1"
+	"testing"
+)
+
+func newCandidate(name, version string, ready bool) capability.Capability {
+	c := capability.Capability{}
+	c.Name = name
+	c.Spec.Version = version
+	if ready {
+		c.Status.Reason = halkyon.ReasonReady
+	}
+	return c
+}
+
+func TestPreferredCapability(t *testing.T) {
+	required := v1beta1.RequiredCapabilityConfig{Name: "db"}
+	required.Spec.Version = "11"
+	tests := []struct {
+		name       string
+		candidates []capability.Capability
+		expected   string
+	}{
+		{
+			name:       "ready first",
+			candidates: []capability.Capability{newCandidate("db", "11", false), newCandidate("postgres", "10", true)},
+			expected:   "postgres",
+		},
+		{
+			name:       "then exact version",
+			candidates: []capability.Capability{newCandidate("db", "10", true), newCandidate("postgres", "11", true)},
+			expected:   "postgres",
+		},
+		{
+			name:       "then named after the requirement",
+			candidates: []capability.Capability{newCandidate("a-postgres", "11", true), newCandidate("db", "11", true)},
+			expected:   "db",
+		},
+		{
+			name:       "then alphabetical order",
+			candidates: []capability.Capability{newCandidate("postgres-b", "11", true), newCandidate("postgres-a", "11", true)},
+			expected:   "postgres-a",
+		},
+		{
+			name:       "independently of the initial order",
+			candidates: []capability.Capability{newCandidate("postgres-a", "11", true), newCandidate("postgres-b", "11", true)},
+			expected:   "postgres-a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if preferred := preferredCapability(required, tt.candidates); preferred.Name != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, preferred.Name)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ func NewCmdComponent(parent string) *cobra.Command {
 	create := NewCmdCreate(fullName)
 	del := NewCmdDelete(fullName)
 	bind := NewCmdBind(fullName)
+	unbind := NewCmdUnbind(fullName)
 
 	hal := &cobra.Command{
 		Use:   fmt.Sprintf("%s [flags]", commandName),
@@ -33,7 +34,14 @@ func NewCmdComponent(parent string) *cobra.Command {
 		push,
 		mode,
 		bind,
+		unbind,
 		NewCmdLog(fullName),
+		NewCmdExec(fullName),
+		NewCmdShell(fullName),
+		NewCmdDebug(fullName),
+		NewCmdPortForward(fullName),
+		NewCmdList(fullName),
+		NewCmdDescribe(fullName),
 		NewCmdEdit(fullName),
 	)
 
@@ -7,10 +7,10 @@ import (
 	"halkyon.io/api/component/v1beta1"
 	v1beta12 "halkyon.io/api/runtime/v1beta1"
 	halkyon "halkyon.io/api/v1beta1"
+	"halkyon.io/hal/pkg/catalog"
 	"halkyon.io/hal/pkg/cmdutil"
 	"halkyon.io/hal/pkg/hal/cli/capability"
 	"halkyon.io/hal/pkg/io"
-	"halkyon.io/hal/pkg/k8s"
 	"halkyon.io/hal/pkg/ui"
 	"halkyon.io/hal/pkg/validation"
 	"io/ioutil"
@@ -19,19 +19,10 @@ import (
 	ktemplates "k8s.io/kubectl/pkg/util/templates"
 	"os"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
 )
 
-var runtimes = <-getRuntimes()
-
-type halkyonRuntime struct {
-	name      string
-	versions  []string
-	generator string
-}
-
 type createOptions struct {
 	*cmdutil.CreateOptions
 	*cmdutil.EnvOptions
@@ -46,6 +37,7 @@ type createOptions struct {
 	requiredCaps []v1beta1.RequiredCapabilityConfig
 	providedCaps []v1beta1.CapabilityConfig
 	target       *v1beta1.Component
+	catalog      *catalog.Catalog
 }
 
 func (o *createOptions) GeneratePrefix() string {
@@ -62,7 +54,7 @@ func (o *createOptions) Build() runtime.Object {
 		}
 
 		o.target = &v1beta1.Component{
-			TypeMeta: typeMeta(),
+			TypeMeta: TypeMeta(),
 			ObjectMeta: v1.ObjectMeta{
 				Name:      o.Name,
 				Namespace: o.CreateOptions.Client.GetNamespace(),
@@ -79,6 +71,10 @@ func (o *createOptions) Build() runtime.Object {
 				Envs: o.Envs,
 			},
 		}
+		// components created within a project get the environment it shares
+		if project, err := cmdutil.FindCurrentProject(); err == nil && project != nil {
+			project.ApplySharedEnv(o.target)
+		}
 	}
 
 	return o.target
@@ -94,10 +90,25 @@ var (
 )
 
 func (o *createOptions) Complete(name string, cmd *cobra.Command, args []string) error {
-	ui.SelectOrCheckExisting(&o.runtime, "Runtime", o.getRuntimes(), o.isValidRuntime)
-	ui.SelectOrCheckExisting(&o.RuntimeVersion, "Version", o.getVersionsForRuntime(), o.isValidVersionGivenRuntime)
+	if err := cmdutil.CheckRequiredFlags(cmd, "runtime", "runtimeVersion"); err != nil {
+		return err
+	}
+
+	c, err := catalog.Get()
+	if err != nil {
+		return err
+	}
+	o.catalog = c
+
+	if err := ui.SelectOrCheckExisting(&o.runtime, "Runtime", o.getRuntimes(), o.isValidRuntime); err != nil {
+		return err
+	}
+	if err := ui.SelectOrCheckExisting(&o.RuntimeVersion, "Runtime version", o.getVersionsForRuntime(), o.isValidVersionGivenRuntime); err != nil {
+		return err
+	}
 
 	if len(o.exposeP) == 0 {
+		// not exposed when running non-interactively unless --expose is used
 		o.expose = ui.Proceed("Expose microservice")
 	} else {
 		b, err := strconv.ParseBool(o.exposeP)
@@ -108,7 +119,10 @@ func (o *createOptions) Complete(name string, cmd *cobra.Command, args []string)
 	}
 
 	if o.port == 0 {
-		port := ui.Ask("Port", fmt.Sprintf("%d", o.port), "8080")
+		port, err := ui.Ask("Port", fmt.Sprintf("%d", o.port), "8080")
+		if err != nil {
+			return err
+		}
 		intPort, err := strconv.Atoi(port)
 		if err != nil {
 			return err
@@ -116,9 +130,10 @@ func (o *createOptions) Complete(name string, cmd *cobra.Command, args []string)
 		o.port = intPort
 	}
 
-	r := runtimes[o.runtime]
-	hasGenerator := len(r.generator) > 0
+	r := o.catalog.Runtimes[o.runtime]
+	hasGenerator := len(r.Generator) > 0
 	if len(o.scaffoldP) == 0 {
+		// no code is generated when running non-interactively unless --scaffold is used
 		o.scaffold = hasGenerator && ui.Proceed("Use code generator")
 	} else {
 		b, err := strconv.ParseBool(o.scaffoldP)
@@ -126,26 +141,37 @@ func (o *createOptions) Complete(name string, cmd *cobra.Command, args []string)
 			return err
 		}
 		if b && !hasGenerator {
-			ui.OutputError(fmt.Sprintf("ignoring scaffolding option because unsupported by %s runtime", r.name))
+			ui.OutputError(fmt.Sprintf("ignoring scaffolding option because unsupported by %s runtime", r.Name))
 		}
 		o.scaffold = hasGenerator && b
 	}
 
 	if o.scaffold {
-		o.GroupId = ui.Ask("Group Id", o.GroupId, "dev.snowdrop")
-		o.ArtifactId = ui.Ask("Artifact Id", o.ArtifactId, "myproject")
-		o.ProjectVersion = ui.Ask("Version", o.ProjectVersion, "1.0.0-SNAPSHOT")
-		o.PackageName = ui.Ask("Package name", o.PackageName, o.GroupId+"."+o.ArtifactId)
-		o.generator = r.generator // set the generator url to the unparsed runtime generator url to be filled in Validate
+		if o.GroupId, err = ui.Ask("Group Id", o.GroupId, "dev.snowdrop"); err != nil {
+			return err
+		}
+		if o.ArtifactId, err = ui.Ask("Artifact Id", o.ArtifactId, "myproject"); err != nil {
+			return err
+		}
+		if o.ProjectVersion, err = ui.Ask("Version", o.ProjectVersion, "1.0.0-SNAPSHOT"); err != nil {
+			return err
+		}
+		if o.PackageName, err = ui.Ask("Package name", o.PackageName, o.GroupId+"."+o.ArtifactId); err != nil {
+			return err
+		}
+		o.generator = r.Generator // set the generator url to the unparsed runtime generator url to be filled in Validate
 		o.scaffold = true
 	} else {
 		o.scaffold = false
 		names := o.getChildDirNames()
 		if len(names) > 0 {
-			ui.SelectOrCheckExisting(&o.Name, "Local component directory", names, func() bool { return true })
+			if err := ui.SelectOrCheckExisting(&o.Name, "Local component directory", names, func() bool { return true }); err != nil {
+				return err
+			}
 		}
 	}
 
+	// capabilities can only be added interactively
 	if ui.Proceed("Requires capabilities") {
 		required := v1beta1.RequiredCapabilityConfig{}
 		o.requiredCaps = make([]v1beta1.RequiredCapabilityConfig, 0, 10)
@@ -157,7 +183,10 @@ func (o *createOptions) Complete(name string, cmd *cobra.Command, args []string)
 				break
 			}
 			if hasCaps && ui.Proceed("Bind to existing capability") {
-				displayable := ui.SelectDisplayable("Target capability", existing)
+				displayable, err := ui.SelectDisplayable("Target capability", existing)
+				if err != nil {
+					return err
+				}
 				required.BoundTo = displayable.Name()
 				required.Spec = displayable.GetUnderlying().(v1beta13.Capability).Spec
 			} else {
@@ -241,7 +270,13 @@ func (o *createOptions) Validate() error {
 		}
 		return nil
 	} else if !validation.IsValidDir(o.Name) {
-		if len(children) == 0 || ui.Proceed(fmt.Sprintf("no directory named '%s' exists in %v, create it", o.Name, currentDir)) {
+		create := len(children) == 0
+		if !create {
+			if create, err = ui.Confirm(fmt.Sprintf("no directory named '%s' exists in %v, create it", o.Name, currentDir)); err != nil {
+				return err
+			}
+		}
+		if create {
 			// if we're not scaffolding and we don't have any existing children directory, create one
 			err := os.Mkdir(o.Name, os.ModePerm)
 			if err != nil {
@@ -256,7 +291,7 @@ func (o *createOptions) Validate() error {
 }
 
 func (o *createOptions) getRuntimes() []string {
-	return getRuntimeNames()
+	return o.catalog.RuntimeNames()
 }
 
 func (o *createOptions) isValidRuntime() bool {
@@ -264,11 +299,11 @@ func (o *createOptions) isValidRuntime() bool {
 }
 
 func (o *createOptions) getVersionsForRuntime() []string {
-	r, ok := runtimes[o.runtime]
+	r, ok := o.catalog.Runtimes[o.runtime]
 	if !ok {
 		return []string{"Unknown runtime " + o.runtime} // shouldn't happen
 	}
-	return r.versions
+	return r.Versions
 }
 
 func (o *createOptions) isValidVersionGivenRuntime() bool {
@@ -293,45 +328,34 @@ func (o *createOptions) getChildDirNames() []string {
 	return childDirs
 }
 
-func getRuntimes() chan map[string]*halkyonRuntime {
-	r := make(chan map[string]*halkyonRuntime)
+// runtimeFlagUsage only relies on the cached catalog since contacting the cluster just to display help isn't desirable
+func runtimeFlagUsage() string {
+	usage := "Runtime to use for the component"
+	if names := catalog.Cached().RuntimeNames(); len(names) > 0 {
+		usage += ". Possible values: " + strings.Join(names, ",")
+	}
+	return usage
+}
 
-	go func() {
-		list, err := k8s.GetClient().HalkyonRuntimeClient.Runtimes().List(v1.ListOptions{})
+// registerRuntimeCompletions completes runtime names and, once a runtime is selected, the versions available for it
+func registerRuntimeCompletions(cmd *cobra.Command, runtime *string) {
+	cmdutil.RegisterFlagCompletion(cmd, "runtime", func(string) []string {
+		c, err := catalog.Get()
 		if err != nil {
-			panic(err)
+			return nil
 		}
-
-		hRuntimes := make(map[string]*halkyonRuntime, 11)
-		for _, item := range list.Items {
-			name := item.Spec.Name
-			runtime, ok := hRuntimes[name]
-			if !ok {
-				runtime = &halkyonRuntime{name: name, generator: item.Spec.GeneratorTemplate}
-				hRuntimes[name] = runtime
-			}
-
-			versions := runtime.versions
-			if len(versions) == 0 {
-				versions = make([]string, 0, 7)
-			}
-			versions = append(versions, item.Spec.Version)
-			runtime.versions = versions
+		return c.RuntimeNames()
+	})
+	cmdutil.RegisterFlagCompletion(cmd, "runtimeVersion", func(string) []string {
+		c, err := catalog.Get()
+		if err != nil {
+			return nil
 		}
-
-		r <- hRuntimes
-	}()
-
-	return r
-}
-
-func getRuntimeNames() []string {
-	result := make([]string, 0, len(runtimes))
-	for k := range runtimes {
-		result = append(result, k)
-	}
-	sort.Strings(result)
-	return result
+		if r, ok := c.Runtimes[*runtime]; ok {
+			return r.Versions
+		}
+		return nil
+	})
 }
 
 func (o *createOptions) SetEnvOptions(env *cmdutil.EnvOptions) {
@@ -347,7 +371,7 @@ func NewCmdCreate(fullParentName string) *cobra.Command {
 	cmd := cmdutil.NewGenericCreate(fullParentName, generic)
 	cmd.Example = fmt.Sprintf(createExample, cmdutil.CommandName(cmd.Name(), fullParentName))
 
-	cmd.Flags().StringVarP(&o.runtime, "runtime", "r", "", "Runtime to use for the component. Possible values:"+strings.Join(getRuntimeNames(), ","))
+	cmd.Flags().StringVarP(&o.runtime, "runtime", "r", "", runtimeFlagUsage())
 	cmd.Flags().StringVarP(&o.RuntimeVersion, "runtimeVersion", "i", "", "Runtime version")
 	cmd.Flags().StringVarP(&o.exposeP, "expose", "x", "", "Whether or not to expose the microservice outside of the cluster")
 	cmd.Flags().IntVarP(&o.port, "port", "o", 0, "Port the microservice listens on")
@@ -358,6 +382,7 @@ func NewCmdCreate(fullParentName string) *cobra.Command {
 	cmd.Flags().StringVarP(&o.ProjectTemplate, "template", "t", "rest", "Template name used to select the project to be created, only supported for Spring Boot")
 	cmd.Flags().StringVarP(&o.PackageName, "packagename", "p", "", "Package name (defaults to <group id>.<artifact id>)")
 
+	registerRuntimeCompletions(cmd, &o.runtime)
 	cmdutil.SetupEnvOptions(o, cmd)
 
 	return cmd
@@ -0,0 +1,214 @@
+package component
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/api/component/v1beta1"
+	halkyon "halkyon.io/api/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/log"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"strings"
+	"time"
+)
+
+const (
+	debugCommandName = "debug"
+	// javaToolOptions is picked up by all JVMs, whatever the way the application is started
+	javaToolOptions = "JAVA_TOOL_OPTIONS"
+	// jdwpAgent configures the JDWP agent to listen on all interfaces, using a syntax supported by Java 8 and later
+	jdwpAgent = "-agentlib:jdwp=transport=dt_socket,server=y,suspend=%s,address=0.0.0.0:%d"
+	// reconcileTimeout controls how long we wait for the operator to redeploy the component after its environment changed
+	reconcileTimeout      = 2 * time.Minute
+	reconcilePollInterval = time.Second
+)
+
+type debugOptions struct {
+	*cmdutil.ComponentTargetingOptions
+	component *v1beta1.Component
+	port      int
+	localPort int
+	suspend   bool
+	binary    bool
+}
+
+var (
+	debugExample = ktemplates.Examples(`  # Debug the current component, attaching the debugger to localhost:5005
+  %[1]s
+
+  # Debug the backend-sb component, waiting for the debugger to be attached before starting the application
+  %[1]s -c backend-sb --suspend`)
+)
+
+func (o *debugOptions) SetTargetingOptions(options *cmdutil.ComponentTargetingOptions) {
+	o.ComponentTargetingOptions = options
+}
+
+func (o *debugOptions) Complete(name string, cmd *cobra.Command, args []string) (err error) {
+	if o.component != nil {
+		return fmt.Errorf("only a single component can be debugged at a time")
+	}
+	o.component, err = Entity.GetTyped(o.GetTargetedComponentName())
+	if err != nil {
+		return err
+	}
+	if o.localPort == 0 {
+		o.localPort = o.port
+	}
+	return nil
+}
+
+func (o *debugOptions) Validate() error {
+	for _, port := range []int{o.port, o.localPort} {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("invalid port %d", port)
+		}
+	}
+	return nil
+}
+
+func (o *debugOptions) Run() error {
+	original := o.component.Spec.Envs
+	suspend := "n"
+	if o.suspend {
+		suspend = "y"
+	}
+	previousPod, err := o.updateEnvs(withJavaAgent(original, fmt.Sprintf(jdwpAgent, suspend, o.port)))
+	if err != nil {
+		return err
+	}
+	// restore the environment as soon as the component was modified, even if the application cannot be restarted
+	defer func() {
+		log.Infof("Restoring '%s' component's environment", o.component.Name)
+		podName, err := o.updateEnvs(original)
+		if err == nil {
+			err = o.restart(podName)
+		}
+		if err != nil {
+			log.Errorf("Couldn't restore '%s' component's environment, %s variable needs to be removed manually: %v", o.component.Name, javaToolOptions, err)
+		}
+	}()
+	if err := o.restart(previousPod); err != nil {
+		return err
+	}
+
+	stop, cleanup := stopOnInterrupt()
+	defer cleanup()
+	mapping := fmt.Sprintf("%d:%d", o.localPort, o.port)
+	return forwardPorts(o.component.Name, []string{mapping}, stop, func(podName string) {
+		log.Successf("Debugging '%s' component using '%s' pod, press Ctrl+C to stop", o.component.Name, podName)
+		log.Infof("Attach your IDE's remote JVM debugger to localhost:%d, for example:", o.localPort)
+		log.Progressf("IntelliJ IDEA: Run > Edit Configurations… > + > Remote JVM Debug, host localhost, port %d", o.localPort)
+		log.Progressf(`VS Code: add a launch configuration with "type": "java", "request": "attach", "hostName": "localhost", "port": %d`, o.localPort)
+		log.Progressf("Eclipse: Run > Debug Configurations… > Remote Java Application, host localhost, port %d", o.localPort)
+		log.Progressf("jdb: jdb -attach localhost:%d", o.localPort)
+	})
+}
+
+// updateEnvs sets the environment of the component, returning the name of the pod that was running before the change
+func (o *debugOptions) updateEnvs(envs []halkyon.NameValuePair) (previousPod string, err error) {
+	c, err := Entity.GetTyped(o.component.Name)
+	if err != nil {
+		return "", err
+	}
+	previousPod = c.Status.GetAssociatedPodName()
+	c.Spec.Envs = envs
+	if _, err = Entity.components().Update(c); err != nil {
+		return "", err
+	}
+	return previousPod, nil
+}
+
+// restart waits for the operator to replace the specified pod so that the new environment is used, then pushes the
+// component again, which restarts the application, since what was previously pushed didn't carry over to the new pod
+func (o *debugOptions) restart(previousPod string) error {
+	c, err := o.waitForNewPod(previousPod)
+	if err != nil {
+		return err
+	}
+	push := o.redeployOptions()
+	if len(c.Spec.Revision) > 0 {
+		result, err := push.pushTarget()
+		if err != nil || result == pushSucceeded {
+			return err
+		}
+	}
+	// nothing needed to be pushed, only restart the application
+	pipeline, err := push.resolvePipeline(c)
+	if err != nil {
+		return err
+	}
+	return push.runSteps(c.Status.GetAssociatedPodName(), pipeline, pipeline.Restart)
+}
+
+// redeployOptions returns the options used to push the component again once its pod was replaced, the local descriptor
+// being left untouched since the component's environment is only changed while debugging
+func (o *debugOptions) redeployOptions() *pushOptions {
+	return &pushOptions{ComponentTargetingOptions: o.ComponentTargetingOptions, binary: o.binary, keepDescriptor: true}
+}
+
+// waitForNewPod waits until the component is ready with a pod other than the specified one, since the component's
+// status still reflects the previous pod right after its spec is updated
+func (o *debugOptions) waitForNewPod(previousPod string) (*v1beta1.Component, error) {
+	s := log.Spinner("Waiting for '" + o.component.Name + "' component to be redeployed…")
+	defer s.End(false)
+	timeout := time.After(reconcileTimeout)
+	for {
+		c, err := Entity.GetTyped(o.component.Name)
+		if err != nil {
+			return nil, err
+		}
+		if err := errorIfFailedOrUnknown(c); err != nil {
+			return nil, err
+		}
+		podName := c.Status.GetAssociatedPodName()
+		if c.Status.Reason == halkyon.ReasonReady && len(podName) > 0 && podName != previousPod {
+			s.End(true)
+			return c, nil
+		}
+		select {
+		case <-timeout:
+			return nil, fmt.Errorf("'%s' component wasn't redeployed within %v, use 'describe' to find out more about its status", o.component.Name, reconcileTimeout)
+		case <-time.After(reconcilePollInterval):
+		}
+	}
+}
+
+// withJavaAgent returns a copy of the specified environment where the specified agent is added to JAVA_TOOL_OPTIONS
+func withJavaAgent(envs []halkyon.NameValuePair, agent string) []halkyon.NameValuePair {
+	result := make([]halkyon.NameValuePair, 0, len(envs)+1)
+	found := false
+	for _, env := range envs {
+		if env.Name == javaToolOptions {
+			env.Value = strings.TrimSpace(env.Value + " " + agent)
+			found = true
+		}
+		result = append(result, env)
+	}
+	if !found {
+		result = append(result, halkyon.NameValuePair{Name: javaToolOptions, Value: agent})
+	}
+	return result
+}
+
+func NewCmdDebug(fullParentName string) *cobra.Command {
+	o := &debugOptions{}
+	debug := &cobra.Command{
+		Use:   fmt.Sprintf("%s [flags]", debugCommandName),
+		Short: "Debug a JVM component from your IDE",
+		Long: `Debug a JVM component from your IDE.
+
+The JDWP agent is added to the component's JAVA_TOOL_OPTIONS environment variable, the component being redeployed and
+pushed again, the debug port being then forwarded locally until the command is interrupted, at which point the
+component's original environment is restored, redeploying and pushing it again. Use --binary if the component was
+pushed as a packaged binary.`,
+		Example: fmt.Sprintf(debugExample, cmdutil.CommandName(debugCommandName, fullParentName)),
+		Args:    cobra.NoArgs,
+	}
+	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, debug)
+	debug.Flags().IntVar(&o.port, "port", 5005, "Port the JDWP agent listens on in the container")
+	debug.Flags().IntVar(&o.localPort, "local-port", 0, "Local port to attach the debugger to, defaults to the agent's port")
+	debug.Flags().BoolVar(&o.suspend, "suspend", false, "Wait for the debugger to be attached before starting the application")
+	debug.Flags().BoolVarP(&o.binary, "binary", "b", false, "Push the packaged binary instead of source code when redeploying the component")
+	return debug
+}
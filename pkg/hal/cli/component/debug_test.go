@@ -0,0 +1,73 @@
+package component
+
+import (
+	halkyon "halkyon.io/api/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"reflect"
+	"testing"
+)
+
+func TestWithJavaAgent(t *testing.T) {
+	const agent = "-agentlib:jdwp=transport=dt_socket,server=y,suspend=n,address=0.0.0.0:5005"
+	tests := []struct {
+		name     string
+		envs     []halkyon.NameValuePair
+		expected []halkyon.NameValuePair
+	}{
+		{
+			name:     "no environment",
+			expected: []halkyon.NameValuePair{{Name: javaToolOptions, Value: agent}},
+		},
+		{
+			name: "other variables are kept",
+			envs: []halkyon.NameValuePair{{Name: "PROFILE", Value: "dev"}},
+			expected: []halkyon.NameValuePair{
+				{Name: "PROFILE", Value: "dev"},
+				{Name: javaToolOptions, Value: agent},
+			},
+		},
+		{
+			name: "existing options are kept",
+			envs: []halkyon.NameValuePair{{Name: javaToolOptions, Value: "-Xmx512m"}, {Name: "PROFILE", Value: "dev"}},
+			expected: []halkyon.NameValuePair{
+				{Name: javaToolOptions, Value: "-Xmx512m " + agent},
+				{Name: "PROFILE", Value: "dev"},
+			},
+		},
+		{
+			name:     "empty options",
+			envs:     []halkyon.NameValuePair{{Name: javaToolOptions}},
+			expected: []halkyon.NameValuePair{{Name: javaToolOptions, Value: agent}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var original []halkyon.NameValuePair
+			original = append(original, tt.envs...)
+			result := withJavaAgent(tt.envs, agent)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+			if !reflect.DeepEqual(tt.envs, original) {
+				t.Errorf("original environment was modified: %v", tt.envs)
+			}
+		})
+	}
+}
+
+func TestDebugRedeployOptions(t *testing.T) {
+	for _, binary := range []bool{false, true} {
+		targeting := cmdutil.NewTargetingOptions()
+		o := &debugOptions{ComponentTargetingOptions: targeting, binary: binary}
+		push := o.redeployOptions()
+		if push.ComponentTargetingOptions != targeting {
+			t.Errorf("redeployment should target the debugged component")
+		}
+		if push.binary != binary {
+			t.Errorf("expected binary mode to be %v when redeploying", binary)
+		}
+		if !push.keepDescriptor {
+			t.Errorf("the debugging environment shouldn't be recorded in the local descriptor")
+		}
+	}
+}
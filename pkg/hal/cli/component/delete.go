@@ -1,11 +1,69 @@
 package component
 
 import (
+	"fmt"
 	"github.com/spf13/cobra"
+	"halkyon.io/api/component/v1beta1"
 	"halkyon.io/hal/pkg/cmdutil"
+	"sort"
+	"strings"
 )
 
+type deleter struct {
+	cascade bool
+}
+
+// Plan also deletes the capabilities that are only bound to the deleted components when cascading
+func (d *deleter) Plan(names []string) ([]cmdutil.PlannedDeletion, error) {
+	if !d.cascade {
+		return nil, nil
+	}
+	deleted := make(map[string]bool, len(names))
+	for _, name := range names {
+		deleted[name] = true
+	}
+	components, err := Entity.List()
+	if err != nil {
+		return nil, err
+	}
+	// capabilities bound to deleted components, along with these components, and capabilities used by remaining ones
+	boundBy := make(map[string][]string, len(names))
+	stillUsed := make(map[string]bool, len(components))
+	for _, object := range components {
+		c := object.(*v1beta1.Component)
+		for _, required := range c.Spec.Capabilities.Requires {
+			if len(required.BoundTo) == 0 {
+				continue
+			}
+			if !deleted[c.Name] {
+				stillUsed[required.BoundTo] = true
+			} else if bound := boundBy[required.BoundTo]; len(bound) == 0 || bound[len(bound)-1] != c.Name {
+				boundBy[required.BoundTo] = append(bound, c.Name)
+			}
+		}
+	}
+
+	plan := make([]cmdutil.PlannedDeletion, 0, len(boundBy))
+	for name, bound := range boundBy {
+		if !stillUsed[name] {
+			plan = append(plan, cmdutil.PlannedDeletion{
+				ResourceType: cmdutil.Capability,
+				Name:         name,
+				Reason:       fmt.Sprintf("only bound to %s", strings.Join(bound, ", ")),
+			})
+		}
+	}
+	sort.Slice(plan, func(i, j int) bool {
+		return plan[i].Name < plan[j].Name
+	})
+	return plan, nil
+}
+
 func NewCmdDelete(fullParentName string) *cobra.Command {
+	d := &deleter{}
 	generic := cmdutil.NewDeleteOptions("component", Entity)
-	return cmdutil.NewGenericDelete(fullParentName, generic)
+	generic.Delegate = d
+	cmd := cmdutil.NewGenericDelete(fullParentName, generic)
+	cmd.Flags().BoolVar(&d.cascade, "cascade", false, "Also delete the capabilities that are only bound to the deleted components")
+	return cmd
 }
@@ -0,0 +1,203 @@
+package component
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/api/component/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/hal/cli/capability"
+	"halkyon.io/hal/pkg/k8s"
+	"halkyon.io/hal/pkg/log"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/duration"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	describeCommandName = "describe"
+	// maxDisplayedEvents controls how many of the most recent events are displayed
+	maxDisplayedEvents = 20
+)
+
+type describeOptions struct {
+	*cmdutil.ComponentTargetingOptions
+}
+
+var (
+	describeExample = ktemplates.Examples(`  # Describe the backend-sb component, its pod, bindings and recent events
+  %[1]s -c backend-sb`)
+)
+
+func (o *describeOptions) SetTargetingOptions(options *cmdutil.ComponentTargetingOptions) {
+	o.ComponentTargetingOptions = options
+}
+
+func (o *describeOptions) Complete(name string, cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *describeOptions) Validate() error {
+	return nil
+}
+
+func (o *describeOptions) Run() error {
+	c, err := Entity.GetTyped(o.GetTargetedComponentName())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(log.GetStdout(), 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", c.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", c.Namespace)
+	fmt.Fprintf(w, "Runtime:\t%s/%s\n", c.Spec.Runtime, c.Spec.Version)
+	fmt.Fprintf(w, "Mode:\t%s\n", c.Spec.DeploymentMode.String())
+	fmt.Fprintf(w, "Port:\t%d\n", c.Spec.Port)
+	exposed := "no"
+	if c.Spec.ExposeService {
+		exposed = "yes"
+		if url, ok := getExposedURLs()[c.Name]; ok {
+			exposed = url
+		}
+	}
+	fmt.Fprintf(w, "Exposed:\t%s\n", exposed)
+	fmt.Fprintf(w, "Revision:\t%s\n", valueOrNone(c.Spec.Revision))
+	fmt.Fprintf(w, "Status:\t%s\n", valueOrNone(fmt.Sprintf("%v", c.Status.Reason)))
+	fmt.Fprintf(w, "Message:\t%s\n", valueOrNone(c.Status.Message))
+	if len(c.Spec.Envs) > 0 {
+		fmt.Fprintln(w, "Envs:")
+		for _, env := range c.Spec.Envs {
+			fmt.Fprintf(w, "  %s=%s\n", env.Name, env.Value)
+		}
+	}
+
+	related := []string{c.Name}
+	podName := c.Status.GetAssociatedPodName()
+	fmt.Fprintf(w, "Pod:\t%s\n", valueOrNone(podName))
+	if len(podName) > 0 {
+		related = append(related, podName)
+		describePod(w, podName)
+	}
+
+	related = append(related, describeCapabilities(w, c)...)
+
+	fmt.Fprintln(w, "Events:")
+	describeEvents(w, related)
+
+	return w.Flush()
+}
+
+func describePod(w io.Writer, podName string) {
+	client := k8s.GetClient()
+	pod, err := client.KubeClient.CoreV1().Pods(client.Namespace).Get(podName, v1.GetOptions{})
+	if err != nil {
+		fmt.Fprintf(w, "  Error:\t%v\n", err)
+		return
+	}
+	fmt.Fprintf(w, "  Phase:\t%s\n", pod.Status.Phase)
+	if len(pod.Status.Message) > 0 {
+		fmt.Fprintf(w, "  Message:\t%s\n", pod.Status.Message)
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		state := "running"
+		if status.State.Waiting != nil {
+			state = fmt.Sprintf("waiting (%s)", status.State.Waiting.Reason)
+		} else if status.State.Terminated != nil {
+			state = fmt.Sprintf("terminated (%s, exit code %d)", status.State.Terminated.Reason, status.State.Terminated.ExitCode)
+		}
+		fmt.Fprintf(w, "  Container %s:\t%s, ready: %v, restarts: %d\n", status.Name, state, status.Ready, status.RestartCount)
+	}
+}
+
+// describeCapabilities outputs the required and provided capabilities of the specified component, along with the status
+// of the capabilities required ones are bound to, returning the names of these bound capabilities
+func describeCapabilities(w io.Writer, c *v1beta1.Component) []string {
+	bound := make([]string, 0, len(c.Spec.Capabilities.Requires))
+	if len(c.Spec.Capabilities.Requires) > 0 {
+		fmt.Fprintln(w, "Requires:")
+		for _, required := range c.Spec.Capabilities.Requires {
+			binding := "not bound"
+			if len(required.BoundTo) > 0 {
+				bound = append(bound, required.BoundTo)
+				target, err := capability.Entity.GetTyped(required.BoundTo)
+				if err != nil {
+					binding = fmt.Sprintf("bound to %s (error: %v)", required.BoundTo, err)
+				} else {
+					binding = fmt.Sprintf("bound to %s [%s]", required.BoundTo, valueOrNone(fmt.Sprintf("%v", target.Status.Reason)))
+					if len(target.Status.Message) > 0 {
+						binding = fmt.Sprintf("%s: %s", binding, target.Status.Message)
+					}
+				}
+			} else if required.AutoBindable {
+				binding = "not bound (auto-bindable)"
+			}
+			fmt.Fprintf(w, "  %s\t%s\n", capability.GetDisplay(required.Name, required.Spec), binding)
+		}
+	}
+	if len(c.Spec.Capabilities.Provides) > 0 {
+		fmt.Fprintln(w, "Provides:")
+		for _, provided := range c.Spec.Capabilities.Provides {
+			fmt.Fprintf(w, "  %s\n", capability.GetDisplay(provided.Name, provided.Spec))
+		}
+	}
+	return bound
+}
+
+// describeEvents outputs the most recent events associated with the objects with the specified names
+func describeEvents(w io.Writer, names []string) {
+	client := k8s.GetClient()
+	events := make([]corev1.Event, 0, 11)
+	for _, name := range names {
+		list, err := client.KubeClient.CoreV1().Events(client.Namespace).List(v1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("involvedObject.name", name).String(),
+		})
+		if err != nil {
+			fmt.Fprintf(w, "  Error retrieving events for %s:\t%v\n", name, err)
+			continue
+		}
+		events = append(events, list.Items...)
+	}
+	if len(events) == 0 {
+		fmt.Fprintln(w, "  <none>")
+		return
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+	if len(events) > maxDisplayedEvents {
+		events = events[len(events)-maxDisplayedEvents:]
+	}
+	fmt.Fprintln(w, "  TYPE\tREASON\tAGE\tOBJECT\tMESSAGE")
+	for _, event := range events {
+		object := strings.ToLower(event.InvolvedObject.Kind) + "/" + event.InvolvedObject.Name
+		age := duration.HumanDuration(time.Since(eventTime(event)))
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%s\n", event.Type, event.Reason, age, object, strings.TrimSpace(event.Message))
+	}
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.FirstTimestamp.Time
+}
+
+func NewCmdDescribe(fullParentName string) *cobra.Command {
+	o := &describeOptions{}
+	describe := &cobra.Command{
+		Use:     fmt.Sprintf("%s [flags]", describeCommandName),
+		Short:   "Describe the component, its pod, bindings and recent events",
+		Long:    `Describe the component, its pod, bindings and recent events.`,
+		Example: fmt.Sprintf(describeExample, cmdutil.CommandName(describeCommandName, fullParentName)),
+		Args:    cobra.NoArgs,
+	}
+	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, describe)
+	return describe
+}
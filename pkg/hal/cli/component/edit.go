@@ -7,6 +7,7 @@ import (
 	"halkyon.io/api/component/v1beta1"
 	v1beta12 "halkyon.io/api/runtime/v1beta1"
 	halkyon "halkyon.io/api/v1beta1"
+	"halkyon.io/hal/pkg/catalog"
 	"halkyon.io/hal/pkg/cmdutil"
 	"halkyon.io/hal/pkg/hal/cli/capability"
 	"halkyon.io/hal/pkg/ui"
@@ -28,6 +29,7 @@ type editOptions struct {
 	requiredCaps []v1beta1.RequiredCapabilityConfig
 	providedCaps []v1beta1.CapabilityConfig
 	target       *v1beta1.Component
+	catalog      *catalog.Catalog
 }
 
 func (o *editOptions) GeneratePrefix() string {
@@ -48,18 +50,32 @@ func (o *editOptions) Set(entity runtime.Object) {
 }
 
 func (o *editOptions) Complete(name string, cmd *cobra.Command, args []string) error {
-	ui.SelectOrCheckExisting(&o.runtime, "Runtime", o.getRuntimes(), o.isValidRuntime)
-	ui.SelectOrCheckExisting(&o.RuntimeVersion, "Version", o.getVersionsForRuntime(), o.isValidVersionGivenRuntime)
+	c, err := catalog.Get()
+	if err != nil {
+		return err
+	}
+	o.catalog = c
 
+	if err := ui.SelectOrCheckExisting(&o.runtime, "Runtime", o.getRuntimes(), o.isValidRuntime); err != nil {
+		return err
+	}
+	if err := ui.SelectOrCheckExisting(&o.RuntimeVersion, "Runtime version", o.getVersionsForRuntime(), o.isValidVersionGivenRuntime); err != nil {
+		return err
+	}
+
+	// capabilities are left unchanged when running non-interactively
 	if ui.Proceed("Edit required capabilities") {
 		for {
-			done, remove, disp := displayAndControl("required", func() ui.DisplayableMap {
+			done, remove, disp, err := displayAndControl("required", func() ui.DisplayableMap {
 				result := ui.NewDisplayableMap(len(o.target.Spec.Capabilities.Requires))
 				for i, required := range o.target.Spec.Capabilities.Requires {
 					result.Add(displayable{element: required, index: i})
 				}
 				return result
 			})
+			if err != nil {
+				return err
+			}
 			if done {
 				break
 			}
@@ -72,12 +88,17 @@ func (o *editOptions) Complete(name string, cmd *cobra.Command, args []string) e
 			if add != disp.Name() {
 				required = disp.GetUnderlying().(v1beta1.RequiredCapabilityConfig)
 			}
-			required.Name = ui.Ask("Name", "", required.Name)
+			if required.Name, err = ui.Ask("Name", "", required.Name); err != nil {
+				return err
+			}
 
 			existing := capability.Entity.GetMatching()
 			hasCaps := existing.Len() > 0
 			if hasCaps && ui.Proceed("Bind to existing capability") {
-				displayable := ui.SelectDisplayable("Target capability", existing)
+				displayable, err := ui.SelectDisplayable("Target capability", existing)
+				if err != nil {
+					return err
+				}
 				required.BoundTo = displayable.Name()
 				required.Spec = displayable.GetUnderlying().(v1beta13.Capability).Spec
 			} else {
@@ -113,13 +134,16 @@ func (o *editOptions) Complete(name string, cmd *cobra.Command, args []string) e
 
 	if ui.Proceed("Edit provides capabilities") {
 		for {
-			done, remove, disp := displayAndControl("provided", func() ui.DisplayableMap {
+			done, remove, disp, err := displayAndControl("provided", func() ui.DisplayableMap {
 				result := ui.NewDisplayableMap(len(o.target.Spec.Capabilities.Provides))
 				for i, required := range o.target.Spec.Capabilities.Provides {
 					result.Add(displayable{element: required, index: i})
 				}
 				return result
 			})
+			if err != nil {
+				return err
+			}
 			if done {
 				break
 			}
@@ -132,7 +156,9 @@ func (o *editOptions) Complete(name string, cmd *cobra.Command, args []string) e
 			if add != disp.Name() {
 				provided = disp.GetUnderlying().(v1beta1.CapabilityConfig)
 			}
-			provided.Name = ui.Ask("Name", "", provided.Name)
+			if provided.Name, err = ui.Ask("Name", "", provided.Name); err != nil {
+				return err
+			}
 			capCreate := capability.CapabilityCreateOptions{}
 			if err := capCreate.Complete(); err != nil {
 				return err
@@ -160,35 +186,48 @@ const add = "__add__"
 const done = "__done__"
 const remove = "__remove__"
 
-func displayAndControl(capType string, displayableMap func() ui.DisplayableMap) (bool, bool, ui.Displayable) {
+func displayAndControl(capType string, displayableMap func() ui.DisplayableMap) (bool, bool, ui.Displayable, error) {
 	result := displayableMap()
 	if result.Len() > 0 {
 		result.Add(ui.NewControlDisplayable(remove, ui.ControlString(fmt.Sprintf("- Remove %s capability", capType))))
 	}
 	result.Add(ui.NewControlDisplayable(add, ui.ControlString(fmt.Sprintf("+ Add new %s capability", capType))))
 	result.Add(ui.NewControlDisplayable(done, ui.ControlString("✓ Done (select to exit)")))
-	displayable := ui.SelectDisplayable(fmt.Sprintf("Select or add %s capabilities", capType), result)
+	displayable, err := ui.SelectDisplayable(fmt.Sprintf("Select or add %s capabilities", capType), result)
+	if err != nil {
+		return false, false, nil, err
+	}
 
 	if done == displayable.Name() {
-		return true, false, nil
+		return true, false, nil, nil
 	}
 
 	if remove == displayable.Name() {
 		result := displayableMap()
-		d := ui.SelectDisplayable(fmt.Sprintf("Select %s capability to remove", capType), result)
+		d, err := ui.SelectDisplayable(fmt.Sprintf("Select %s capability to remove", capType), result)
+		if err != nil {
+			return false, false, nil, err
+		}
 		if ui.Proceed("Really remove") {
-			return false, true, d
+			return false, true, d, nil
 		}
-		return false, true, nil
+		return false, true, nil, nil
 	}
-	return false, false, displayable
+	return false, false, displayable, nil
 }
 
 func (o *editOptions) Validate() error {
 	currentDir, _ := os.Getwd()
 	children := o.getChildDirNames()
 	if !validation.IsValidDir(o.Name) {
-		if len(children) == 0 || ui.Proceed(fmt.Sprintf("no directory named '%s' exists in %v, create it", o.Name, currentDir)) {
+		create := len(children) == 0
+		if !create {
+			var err error
+			if create, err = ui.Confirm(fmt.Sprintf("no directory named '%s' exists in %v, create it", o.Name, currentDir)); err != nil {
+				return err
+			}
+		}
+		if create {
 			// if we're not scaffolding and we don't have any existing children directory, create one
 			err := os.Mkdir(o.Name, os.ModePerm)
 			if err != nil {
@@ -203,7 +242,7 @@ func (o *editOptions) Validate() error {
 }
 
 func (o *editOptions) getRuntimes() []string {
-	return getRuntimeNames()
+	return o.catalog.RuntimeNames()
 }
 
 func (o *editOptions) isValidRuntime() bool {
@@ -211,11 +250,11 @@ func (o *editOptions) isValidRuntime() bool {
 }
 
 func (o *editOptions) getVersionsForRuntime() []string {
-	r, ok := runtimes[o.runtime]
+	r, ok := o.catalog.Runtimes[o.runtime]
 	if !ok {
 		return []string{"Unknown runtime " + o.runtime} // shouldn't happen
 	}
-	return r.versions
+	return r.Versions
 }
 
 func (o *editOptions) isValidVersionGivenRuntime() bool {
@@ -254,11 +293,13 @@ func NewCmdEdit(fullParentName string) *cobra.Command {
 	cmd := cmdutil.NewGenericCreate(fullParentName, generic)
 	cmd.Example = fmt.Sprintf(createExample, cmdutil.CommandName(cmd.Name(), fullParentName))
 
-	cmd.Flags().StringVarP(&o.runtime, "runtime", "r", "", "Runtime to use for the component. Possible values:"+strings.Join(getRuntimeNames(), ","))
+	cmd.Flags().StringVarP(&o.runtime, "runtime", "r", "", runtimeFlagUsage())
 	cmd.Flags().StringVarP(&o.RuntimeVersion, "runtimeVersion", "i", "", "Runtime version")
 	cmd.Flags().StringVarP(&o.exposeP, "expose", "x", "", "Whether or not to expose the microservice outside of the cluster")
 	cmd.Flags().IntVarP(&o.port, "port", "o", 0, "Port the microservice listens on")
 
+	registerRuntimeCompletions(cmd, &o.runtime)
+	cmdutil.RegisterArgsCompletion(cmd, cmdutil.EntityNamesCompletion(cmdutil.Component))
 	cmdutil.SetupEnvOptions(o, cmd)
 
 	return cmd
@@ -12,9 +12,12 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
-type client struct {
-	client v1beta1.ComponentInterface
-	ns     string
+// client lazily connects to the cluster so that commands not needing it can run without one
+type client struct{}
+
+func (lc client) components() v1beta1.ComponentInterface {
+	c := k8s.GetClient()
+	return c.HalkyonComponentClient.Components(c.Namespace)
 }
 
 var _ cmdutil.HalkyonEntity = client{}
@@ -24,10 +27,10 @@ func (lc client) Create(toCreate runtime.Object) error {
 	c, err := lc.Get(component.Name)
 	if errors.IsNotFound(err) {
 		// create
-		_, err = lc.client.Create(component)
+		_, err = lc.components().Create(component)
 	} else if err == nil {
 		component.ResourceVersion = c.(*v1beta12.Component).ResourceVersion
-		c, err = lc.client.Update(component)
+		c, err = lc.components().Update(component)
 	}
 
 	return err
@@ -38,7 +41,7 @@ func (lc client) Get(name string) (runtime.Object, error) {
 }
 
 func (lc client) GetTyped(name string) (*v1beta12.Component, error) {
-	return lc.client.Get(name, v1.GetOptions{})
+	return lc.components().Get(name, v1.GetOptions{})
 }
 
 type displayableCapability struct {
@@ -68,7 +71,7 @@ func NewDisplayableCapability(capability v1beta12.Component) ui.Displayable {
 }
 
 func (lc client) GetKnown() ui.DisplayableMap {
-	list, err := lc.client.List(v1.ListOptions{})
+	list, err := lc.components().List(v1.ListOptions{})
 	if err != nil {
 		return ui.Empty
 	}
@@ -80,15 +83,30 @@ func (lc client) GetKnown() ui.DisplayableMap {
 	return result
 }
 
+func (lc client) List() ([]runtime.Object, error) {
+	list, err := lc.components().List(v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]runtime.Object, 0, len(list.Items))
+	for i := range list.Items {
+		item := list.Items[i]
+		item.TypeMeta = TypeMeta()
+		result = append(result, &item)
+	}
+	return result, nil
+}
+
 func (lc client) Delete(name string, options *v1.DeleteOptions) error {
-	return lc.client.Delete(name, options)
+	return lc.components().Delete(name, options)
 }
 
 func (lc client) GetNamespace() string {
-	return lc.ns
+	return k8s.GetClient().Namespace
 }
 
-func typeMeta() v1.TypeMeta {
+// TypeMeta returns the type information of halkyon components
+func TypeMeta() v1.TypeMeta {
 	return v1.TypeMeta{
 		Kind:       v1beta12.Kind,
 		APIVersion: "halkyon.io/v1beta1",
@@ -98,9 +116,5 @@ func typeMeta() v1.TypeMeta {
 var Entity client
 
 func init() {
-	c := k8s.GetClient()
-	Entity = client{
-		client: c.HalkyonComponentClient.Components(c.Namespace),
-		ns:     c.Namespace,
-	}
+	cmdutil.RegisterEntity(cmdutil.Component, Entity)
 }
@@ -0,0 +1,137 @@
+package component
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/api/component/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/k8s"
+	"io"
+	kexec "k8s.io/client-go/util/exec"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"os"
+)
+
+const (
+	execCommandName  = "exec"
+	shellCommandName = "shell"
+	// defaultShell starts bash if available in the container, sh otherwise
+	defaultShell = "if command -v bash > /dev/null; then exec bash; else exec sh; fi"
+)
+
+type execOptions struct {
+	*cmdutil.ComponentTargetingOptions
+	component *v1beta1.Component
+	command   []string
+	stdin     bool
+	tty       bool
+	shell     string
+}
+
+var (
+	execExample = ktemplates.Examples(`  # List the sources pushed to the current component
+  %[1]s -- ls -l /usr/src
+
+  # Run an interactive command in the backend-sb component
+  %[1]s -c backend-sb -it -- top`)
+	shellExample = ktemplates.Examples(`  # Open a shell in the current component
+  %[1]s
+
+  # Open a shell in the backend-sb component using zsh
+  %[1]s -c backend-sb --shell zsh`)
+)
+
+func (o *execOptions) SetTargetingOptions(options *cmdutil.ComponentTargetingOptions) {
+	o.ComponentTargetingOptions = options
+}
+
+func (o *execOptions) Complete(name string, cmd *cobra.Command, args []string) (err error) {
+	if o.component != nil {
+		return fmt.Errorf("commands can only be executed in a single component")
+	}
+	o.component, err = Entity.GetTyped(o.GetTargetedComponentName())
+	if err != nil {
+		return err
+	}
+	if name == shellCommandName {
+		o.stdin = true
+		o.tty = true
+		o.command = []string{"sh", "-c", defaultShell}
+		if len(o.shell) > 0 {
+			o.command = []string{o.shell}
+		}
+	} else {
+		o.command = args
+	}
+	return nil
+}
+
+func (o *execOptions) Validate() error {
+	if o.tty && !o.stdin {
+		return fmt.Errorf("--tty requires --stdin")
+	}
+	if len(o.component.Status.GetAssociatedPodName()) == 0 {
+		return fmt.Errorf("no pod is currently associated with '%s' component", o.component.Name)
+	}
+	return nil
+}
+
+func (o *execOptions) Run() error {
+	var stdin io.Reader
+	if o.stdin {
+		stdin = os.Stdin
+	}
+	podName := o.component.Status.GetAssociatedPodName()
+	if !o.tty {
+		return withExitStatus(k8s.GetClient().ExecInteractive(podName, o.command, stdin, os.Stdout, os.Stderr, nil))
+	}
+
+	session, err := startTerminalSession(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	// restore the terminal before the command's exit status is passed through
+	defer session.Close()
+	return withExitStatus(k8s.GetClient().ExecInteractive(podName, o.command, stdin, os.Stdout, os.Stderr, session))
+}
+
+// withExitStatus converts errors reporting the exit status of the executed command so that hal exits with it
+func withExitStatus(err error) error {
+	if exit, ok := err.(kexec.ExitError); ok {
+		return cmdutil.ExitStatusError{Status: exit.ExitStatus(), Message: exit.Error()}
+	}
+	return err
+}
+
+func NewCmdExec(fullParentName string) *cobra.Command {
+	o := &execOptions{}
+	exec := &cobra.Command{
+		Use:   fmt.Sprintf("%s [flags] -- COMMAND [args...]", execCommandName),
+		Short: "Execute a command in the component's container",
+		Long: `Execute a command in the component's container.
+
+The command's exit status is passed through so that it can be used in scripts.`,
+		Example: fmt.Sprintf(execExample, cmdutil.CommandName(execCommandName, fullParentName)),
+		Args:    cobra.MinimumNArgs(1),
+	}
+	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, exec)
+	exec.Flags().BoolVarP(&o.stdin, "stdin", "i", false, "Pass stdin to the command")
+	exec.Flags().BoolVarP(&o.tty, "tty", "t", false, "Allocate a TTY, stdin needing to be a terminal")
+	return exec
+}
+
+func NewCmdShell(fullParentName string) *cobra.Command {
+	o := &execOptions{}
+	shell := &cobra.Command{
+		Use:   fmt.Sprintf("%s [flags]", shellCommandName),
+		Short: "Open an interactive shell in the component's container",
+		Long: `Open an interactive shell in the component's container, using bash if available, sh otherwise.
+
+The shell's exit status is passed through.`,
+		Example: fmt.Sprintf(shellExample, cmdutil.CommandName(shellCommandName, fullParentName)),
+		Args:    cobra.NoArgs,
+	}
+	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, shell)
+	shell.Flags().StringVar(&o.shell, "shell", "", "Shell to start instead of bash or sh")
+	return shell
+}
@@ -0,0 +1,83 @@
+package component
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/api/component/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/k8s"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"strings"
+)
+
+type lister struct {
+	urls map[string]string
+}
+
+var _ cmdutil.Lister = &lister{}
+
+func (l *lister) Headers() []string {
+	return []string{"name", "runtime", "mode", "status", "capabilities", "url"}
+}
+
+func (l *lister) Row(object runtime.Object) []string {
+	c := object.(*v1beta1.Component)
+	bound := make([]string, 0, len(c.Spec.Capabilities.Requires))
+	for _, required := range c.Spec.Capabilities.Requires {
+		if len(required.BoundTo) > 0 {
+			bound = append(bound, fmt.Sprintf("%s:%s", required.Name, required.BoundTo))
+		}
+	}
+	return []string{
+		c.Name,
+		fmt.Sprintf("%s/%s", c.Spec.Runtime, c.Spec.Version),
+		c.Spec.DeploymentMode.String(),
+		valueOrNone(fmt.Sprintf("%v", c.Status.Reason)),
+		valueOrNone(strings.Join(bound, ",")),
+		valueOrNone(l.getExposedURL(c)),
+	}
+}
+
+// getExposedURL retrieves the URL at which the specified component is exposed, if it is
+func (l *lister) getExposedURL(c *v1beta1.Component) string {
+	if !c.Spec.ExposeService {
+		return ""
+	}
+	if l.urls == nil {
+		l.urls = getExposedURLs()
+	}
+	return l.urls[c.Name]
+}
+
+// getExposedURLs retrieves the URLs of the ingresses of the current namespace, indexed by ingress name which matches
+// the name of the exposed component
+func getExposedURLs() map[string]string {
+	client := k8s.GetClient()
+	list, err := client.KubeClient.ExtensionsV1beta1().Ingresses(client.Namespace).List(v1.ListOptions{})
+	if err != nil {
+		return map[string]string{}
+	}
+	urls := make(map[string]string, len(list.Items))
+	for _, ingress := range list.Items {
+		for _, rule := range ingress.Spec.Rules {
+			if len(rule.Host) > 0 {
+				urls[ingress.Name] = "http://" + rule.Host
+				break
+			}
+		}
+	}
+	return urls
+}
+
+func valueOrNone(value string) string {
+	if len(value) == 0 {
+		return "<none>"
+	}
+	return value
+}
+
+func NewCmdList(fullParentName string) *cobra.Command {
+	generic := cmdutil.NewListOptions(cmdutil.Component, Entity, &lister{})
+	return cmdutil.NewGenericList(fullParentName, generic)
+}
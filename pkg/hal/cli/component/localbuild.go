@@ -0,0 +1,82 @@
+package component
+
+import (
+	"fmt"
+	"halkyon.io/hal/pkg/log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// buildTool describes how to package a project using a given build tool
+type buildTool struct {
+	name string
+	// wrapper is the name of the wrapper script favored over the installed tool when present in the project
+	wrapper string
+	// windowsWrapper is the name of the wrapper script on Windows
+	windowsWrapper string
+	// executable is the name of the tool when installed on the machine
+	executable string
+	// markers are the files identifying projects built by this tool
+	markers []string
+	// args are the arguments to pass to package the project
+	args []string
+}
+
+// buildTools lists the supported build tools, in order of precedence if a project supports several of them
+var buildTools = []buildTool{
+	{name: "Maven", wrapper: "mvnw", windowsWrapper: "mvnw.cmd", executable: "mvn", markers: []string{"pom.xml"}, args: []string{"-B", "package"}},
+	{name: "Gradle", wrapper: "gradlew", windowsWrapper: "gradlew.bat", executable: "gradle", markers: []string{"build.gradle", "build.gradle.kts"}, args: []string{"assemble"}},
+}
+
+// command returns the command to package the project in the specified directory, if this tool can build it
+func (b buildTool) command(dir string) (*exec.Cmd, bool) {
+	wrapper := b.wrapper
+	if runtime.GOOS == "windows" {
+		wrapper = b.windowsWrapper
+	}
+	if wrapperPath := filepath.Join(dir, wrapper); isFile(wrapperPath) {
+		return b.commandIn(dir, wrapperPath), true
+	}
+	for _, marker := range b.markers {
+		if isFile(filepath.Join(dir, marker)) {
+			return b.commandIn(dir, b.executable), true
+		}
+	}
+	return nil, false
+}
+
+func (b buildTool) commandIn(dir, executable string) *exec.Cmd {
+	cmd := exec.Command(executable, b.args...)
+	cmd.Dir = dir
+	return cmd
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// buildLocally packages the targeted component using the build tool it relies on, streaming the build output
+func (o *pushOptions) buildLocally() error {
+	dir := o.GetTargetedComponentPath()
+	for _, tool := range buildTools {
+		cmd, ok := tool.command(dir)
+		if !ok {
+			continue
+		}
+		o.infof("Building '%s' component locally using %s: %s", o.GetTargetedComponentName(), tool.name, strings.Join(cmd.Args, " "))
+		output := log.NewBuildOutput(buildOutputTail, o.stdout())
+		cmd.Stdout = output
+		cmd.Stderr = output
+		err := cmd.Run()
+		output.Flush()
+		if err != nil {
+			return fmt.Errorf("local %s build of '%s' component failed: %v", tool.name, o.GetTargetedComponentName(), err)
+		}
+		return nil
+	}
+	return fmt.Errorf("couldn't find any Maven or Gradle build in %s", dir)
+}
@@ -6,51 +6,151 @@ import (
 	"halkyon.io/api/component/v1beta1"
 	"halkyon.io/hal/pkg/cmdutil"
 	"halkyon.io/hal/pkg/k8s"
+	"halkyon.io/hal/pkg/log"
+	corev1 "k8s.io/api/core/v1"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"sync"
+	"time"
 )
 
-const logCommandName = "log"
+const (
+	logCommandName = "log"
+	// defaultLogTail is the number of lines displayed when neither --tail nor --since is used
+	defaultLogTail = 100
+)
 
 type logOptions struct {
-	component *v1beta1.Component
+	components map[string]*v1beta1.Component
 	*cmdutil.ComponentTargetingOptions
+	follow     bool
+	since      time.Duration
+	tail       int64
+	previous   bool
+	timestamps bool
+	container  string
 }
 
+var (
+	logExample = ktemplates.Examples(`  # Display the last 100 lines of the logs of the current component
+  %[1]s
+
+  # Follow the logs of the client-sb and backend-sb components, starting with the output of the last 5 minutes
+  %[1]s -c client-sb,backend-sb -f --since 5m`)
+)
+
+var _ cmdutil.AllTargetsRunnable = &logOptions{}
+
 func (o *logOptions) SetTargetingOptions(options *cmdutil.ComponentTargetingOptions) {
 	o.ComponentTargetingOptions = options
 }
 
 func (o *logOptions) Complete(name string, cmd *cobra.Command, args []string) (err error) {
 	// get the targeted component
-	o.component, err = Entity.GetTyped(o.GetTargetedComponentName())
+	component, err := Entity.GetTyped(o.GetTargetedComponentName())
 	if err != nil {
 		return err
 	}
+	if o.components == nil {
+		o.components = make(map[string]*v1beta1.Component, 7)
+	}
+	o.components[component.Name] = component
 
+	// only limit the number of lines when no other limit was requested
+	if !cmd.Flags().Changed("tail") && o.since == 0 {
+		o.tail = defaultLogTail
+	}
 	return nil
 }
 
 func (o *logOptions) Validate() error {
+	if len(o.getPodName()) == 0 {
+		return fmt.Errorf("no pod is currently associated with '%s' component", o.GetTargetedComponentName())
+	}
 	return nil
 }
 
 func (o *logOptions) Run() error {
-	podName := o.component.Status.GetAssociatedPodName()
-	if err := k8s.Logs(podName); err != nil {
-		return err
+	return k8s.GetClient().Logs(o.getPodName(), o.asPodLogOptions(), log.GetStdout())
+}
+
+func (o *logOptions) RunOnAllTargets() error {
+	if len(o.components) < 2 {
+		return o.ForEachTarget(o.Run)
 	}
 
-	return nil
+	// stream the logs of all targeted components concurrently, prefixing each line with the component's name
+	names := make([]string, 0, len(o.components))
+	pods := make([]string, 0, len(o.components))
+	_ = o.ForEachTarget(func() error {
+		names = append(names, o.GetTargetedComponentName())
+		pods = append(pods, o.getPodName())
+		return nil
+	})
+	writers := log.NewPrefixWriters(log.GetStdout(), names...)
+	errs := make(chan error, len(names))
+	wg := sync.WaitGroup{}
+	for i := range names {
+		wg.Add(1)
+		go func(name, pod string, writer *log.PrefixWriter) {
+			defer wg.Done()
+			err := k8s.GetClient().Logs(pod, o.asPodLogOptions(), writer)
+			_ = writer.Flush()
+			if err != nil {
+				errs <- fmt.Errorf("%s: %v", name, err)
+			}
+		}(names[i], pods[i], writers[i])
+	}
+	wg.Wait()
+	close(errs)
+
+	var err error
+	for e := range errs {
+		log.Error(e)
+		err = fmt.Errorf("couldn't retrieve logs for all components")
+	}
+	return err
+}
+
+func (o *logOptions) getPodName() string {
+	component, ok := o.components[o.GetTargetedComponentName()]
+	if !ok {
+		return ""
+	}
+	return component.Status.GetAssociatedPodName()
+}
+
+func (o *logOptions) asPodLogOptions() *corev1.PodLogOptions {
+	options := &corev1.PodLogOptions{
+		Container:  o.container,
+		Follow:     o.follow,
+		Previous:   o.previous,
+		Timestamps: o.timestamps,
+	}
+	if o.tail >= 0 {
+		options.TailLines = &o.tail
+	}
+	if o.since > 0 {
+		seconds := int64(o.since.Round(time.Second).Seconds())
+		options.SinceSeconds = &seconds
+	}
+	return options
 }
 
 func NewCmdLog(fullParentName string) *cobra.Command {
 	o := &logOptions{}
-	bind := &cobra.Command{
+	l := &cobra.Command{
 		Use:     fmt.Sprintf("%s [flags]", logCommandName),
 		Short:   "Retrieve the logs for the component",
 		Long:    `Retrieve the logs for the component.`,
-		Example: fmt.Sprintf(modeExample, cmdutil.CommandName(logCommandName, fullParentName)),
+		Example: fmt.Sprintf(logExample, cmdutil.CommandName(logCommandName, fullParentName)),
 		Args:    cobra.NoArgs,
 	}
-	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, bind)
-	return bind
+	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, l)
+	l.Flags().BoolVarP(&o.follow, "follow", "f", false, "Keep streaming the logs as they are produced")
+	l.Flags().DurationVar(&o.since, "since", 0, "Only return logs newer than the provided duration (e.g. 10s, 5m, 1h)")
+	l.Flags().Int64Var(&o.tail, "tail", -1, fmt.Sprintf("Number of lines to display from the end of the logs, -1 to display all of them, defaults to %d unless --since is used", defaultLogTail))
+	l.Flags().BoolVarP(&o.previous, "previous", "p", false, "Display the logs of the previous instance of the container, if any")
+	l.Flags().BoolVar(&o.timestamps, "timestamps", false, "Prefix each line with its timestamp")
+	l.Flags().StringVar(&o.container, "container", "", "Container to retrieve the logs from, defaults to the pod's only container")
+	return l
 }
@@ -64,5 +64,6 @@ func NewCmdMode(fullParentName string) *cobra.Command {
 	}
 	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, mode)
 	mode.Flags().StringVarP(&o.mode.Provided, "mode", "m", "", "Mode to switch to. Possible values: "+o.mode.GetKnownValues())
+	cmdutil.RegisterFlagCompletion(mode, "mode", cmdutil.EnumCompletion(o.mode))
 	return mode
 }
@@ -0,0 +1,146 @@
+package component
+
+import (
+	"fmt"
+	"halkyon.io/hal/pkg/log"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// pushResult records the outcome of pushing a component
+type pushResult int
+
+const (
+	pushFailed pushResult = iota
+	pushSkipped
+	pushSucceeded
+)
+
+func (r pushResult) String() string {
+	switch r {
+	case pushSkipped:
+		return "skipped (no changes)"
+	case pushSucceeded:
+		return "pushed"
+	default:
+		return "failed"
+	}
+}
+
+type targetResult struct {
+	name     string
+	result   pushResult
+	err      error
+	duration time.Duration
+}
+
+// descriptorLock serializes updates of halkyon descriptors by concurrent pushes
+var descriptorLock sync.Mutex
+
+// pushConcurrently pushes all targeted components using a bounded number of workers, each component's output being
+// prefixed by its name. Failing to push a component doesn't prevent the others from being pushed and a summary is
+// displayed once all are processed.
+func (o *pushOptions) pushConcurrently() error {
+	targets := o.Targets()
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.GetTargetedComponentName())
+	}
+	writers := log.NewPrefixWriters(log.GetStdout(), names...)
+
+	workers := o.jobs
+	if workers > len(targets) {
+		workers = len(targets)
+	}
+	log.Infof("Pushing %d components, %d at a time", len(targets), workers)
+
+	results := make([]targetResult, len(targets))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				target := *o
+				target.ComponentTargetingOptions = targets[i]
+				target.out = writers[i]
+				start := time.Now()
+				result, err := target.pushTarget()
+				if err != nil {
+					target.errorf("Couldn't push '%s' component: %v", names[i], err)
+				}
+				_ = writers[i].Flush()
+				results[i] = targetResult{name: names[i], result: result, err: err, duration: time.Since(start)}
+			}
+		}()
+	}
+	for i := range targets {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return printPushSummary(results)
+}
+
+func printPushSummary(results []targetResult) error {
+	failed := 0
+	w := tabwriter.NewWriter(log.GetStdout(), 0, 4, 3, ' ', 0)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "COMPONENT\tRESULT\tDURATION")
+	for _, r := range results {
+		outcome := r.result.String()
+		if r.err != nil {
+			failed++
+			outcome = "failed: " + r.err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", r.name, outcome, r.duration.Round(time.Second))
+	}
+	_ = w.Flush()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d components failed to push", failed, len(results))
+	}
+	return nil
+}
+
+// spinner starts a status for the specified message, plain lines being output instead of a spinner when pushing several
+// components concurrently
+func (o *pushOptions) spinner(message string) *log.Status {
+	if o.out == nil {
+		return log.Spinner(message)
+	}
+	s := log.NewStatus(o.out)
+	s.Start(message, true)
+	return s
+}
+
+func (o *pushOptions) infof(format string, a ...interface{}) {
+	log.Finfof(o.stdout(), format, a...)
+}
+
+func (o *pushOptions) successf(format string, a ...interface{}) {
+	log.Fsuccessf(o.stdout(), format, a...)
+}
+
+func (o *pushOptions) errorf(format string, a ...interface{}) {
+	log.Ferrorf(o.stderr(), format, a...)
+}
+
+// stdout returns where output related to the targeted component should be written
+func (o *pushOptions) stdout() io.Writer {
+	if o.out == nil {
+		return log.GetStdout()
+	}
+	return o.out
+}
+
+// stderr returns where errors related to the targeted component should be written
+func (o *pushOptions) stderr() io.Writer {
+	if o.out == nil {
+		return log.GetStderr()
+	}
+	return o.out
+}
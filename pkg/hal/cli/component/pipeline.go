@@ -0,0 +1,196 @@
+package component
+
+import (
+	"fmt"
+	component "halkyon.io/api/component/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/k8s"
+	"halkyon.io/hal/pkg/log"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"path"
+	"sigs.k8s.io/yaml"
+	"strings"
+	"time"
+)
+
+// pushPipelineAnnotation can be set on a Runtime or, to override it locally, on a component in its halkyon descriptor to
+// define how pushed content is deployed, using the YAML or JSON representation of a pushPipeline
+const pushPipelineAnnotation = "hal.halkyon.io/push-pipeline"
+
+const (
+	// buildOutputTail controls how many lines of the build output are displayed when it fails
+	buildOutputTail = 30
+	// logsGracePeriod controls how long we keep following logs after the build finished to get its last lines
+	logsGracePeriod = time.Second
+)
+
+// pushPipeline describes how to deploy content pushed to a component's container. Each phase is a list of steps, each
+// step's command being executed in the container, without any shell unless explicitly called, after the previous one
+// succeeded.
+type pushPipeline struct {
+	// SourceDir is the directory in which sources are extracted
+	SourceDir string `json:"sourceDir,omitempty"`
+	// BinaryDir is the directory in which binaries are copied
+	BinaryDir string `json:"binaryDir,omitempty"`
+	// PreCopy is executed before extracting a complete set of sources, once the previous ones were removed from SourceDir
+	PreCopy []step `json:"preCopy,omitempty"`
+	// Build is executed after sources are extracted
+	Build []step `json:"build,omitempty"`
+	// Wait is executed after the build, e.g. to wait for a build running in the background to finish
+	Wait []step `json:"wait,omitempty"`
+	// Check is executed after waiting, a failing check meaning that the build failed
+	Check []step `json:"check,omitempty"`
+	// BuildOutputInLogs records whether the build writes its output to the container's logs, e.g. when run in the
+	// background, instead of it being output by the build steps
+	BuildOutputInLogs bool `json:"buildOutputInLogs,omitempty"`
+	// Restart is executed last, to restart the application with the pushed content
+	Restart []step `json:"restart,omitempty"`
+	// origin records where the pipeline was defined so that errors can be traced back to it
+	origin string
+}
+
+type step struct {
+	// Name is displayed while the step is executing, steps without name being executed silently
+	Name    string   `json:"name,omitempty"`
+	Command []string `json:"command"`
+}
+
+// supervisordPipeline is used for runtimes not defining any pipeline, relying on images running supervisord with
+// build and run programs
+var supervisordPipeline = pushPipeline{
+	SourceDir: "/usr/src",
+	BinaryDir: "/deployments/",
+	Build: []step{
+		{Name: "Performing build", Command: []string{"/var/lib/supervisord/bin/supervisord", "ctl", "start", "build"}},
+	},
+	Wait: []step{
+		{Name: "Waiting for build to finish", Command: []string{"bash", "-c", "while /var/lib/supervisord/bin/supervisord ctl status build | grep -q -e RUNNING -e STARTING; do sleep 1; done"}},
+	},
+	Check: []step{
+		{Command: []string{"bash", "-c", "! /var/lib/supervisord/bin/supervisord ctl status build | grep -q -e FATAL -e BACKOFF -e 'exit status [1-9]'"}},
+	},
+	BuildOutputInLogs: true,
+	Restart: []step{
+		{Command: []string{"/var/lib/supervisord/bin/supervisord", "ctl", "stop", "run"}},
+		{Name: "Restarting app", Command: []string{"/var/lib/supervisord/bin/supervisord", "ctl", "start", "run"}},
+	},
+	origin: "default supervisord pipeline",
+}
+
+// resolvePipeline retrieves the pipeline to use for the specified component, looking first at the local descriptor, then
+// at the component's runtime and finally falling back to the supervisord pipeline
+func (o *pushOptions) resolvePipeline(c *component.Component) (pushPipeline, error) {
+	descriptor := cmdutil.LoadAvailableHalkyonEntities(o.GetTargetedComponentPath())
+	if local, ok := descriptor.GetDefinedEntitiesWith(cmdutil.Component)[c.Name]; ok {
+		if definition, ok := local.Entity.(*component.Component).Annotations[pushPipelineAnnotation]; ok {
+			return parsePipeline(definition, local.Path)
+		}
+	}
+
+	runtimes, err := k8s.GetClient().HalkyonRuntimeClient.Runtimes().List(v1.ListOptions{})
+	if err != nil {
+		return pushPipeline{}, err
+	}
+	for _, runtime := range runtimes.Items {
+		if runtime.Spec.Name == c.Spec.Runtime && runtime.Spec.Version == c.Spec.Version {
+			if definition, ok := runtime.Annotations[pushPipelineAnnotation]; ok {
+				return parsePipeline(definition, fmt.Sprintf("'%s' runtime", runtime.Name))
+			}
+			break
+		}
+	}
+
+	return supervisordPipeline, nil
+}
+
+func parsePipeline(definition, origin string) (pushPipeline, error) {
+	p := pushPipeline{}
+	if err := yaml.Unmarshal([]byte(definition), &p); err != nil {
+		return p, fmt.Errorf("invalid push pipeline defined in %s: %v", origin, err)
+	}
+	for _, steps := range [][]step{p.PreCopy, p.Build, p.Wait, p.Check, p.Restart} {
+		for _, s := range steps {
+			if len(s.Command) == 0 {
+				return p, fmt.Errorf("invalid push pipeline defined in %s: steps must define a command", origin)
+			}
+		}
+	}
+	if len(p.SourceDir) == 0 {
+		p.SourceDir = supervisordPipeline.SourceDir
+	}
+	if len(p.BinaryDir) == 0 {
+		p.BinaryDir = supervisordPipeline.BinaryDir
+	}
+	p.origin = origin
+	return p, nil
+}
+
+// cleanUp returns the step removing previously pushed sources, along with the revision marker, from SourceDir
+func (p pushPipeline) cleanUp() step {
+	// unlike a shell glob, find also removes hidden files which are pushed as well
+	return step{Name: "Cleaning up component", Command: []string{"find", p.SourceDir, "-mindepth", "1", "-delete"}}
+}
+
+// revisionMarker returns the path of the file recording which revision the sources in the container correspond to
+func (p pushPipeline) revisionMarker() string {
+	return path.Join(p.SourceDir, ".hal-revision")
+}
+
+// run executes the specified steps of the specified pipeline in the specified pod, stopping at the first failing one.
+// Steps' output is written to the specified BuildOutput, named steps being announced before they're executed in verbose
+// mode while a spinner is displayed during their execution otherwise.
+func (o *pushOptions) run(p pushPipeline, podName string, steps []step, output *log.BuildOutput) error {
+	c := k8s.GetClient()
+	for _, s := range steps {
+		var status *log.Status
+		if len(s.Name) > 0 {
+			if o.verbose {
+				o.infof("%s…", s.Name)
+			} else {
+				status = o.spinner(s.Name)
+			}
+		}
+		err := c.ExecCMDInContainer(podName, s.Command, output, output, nil, false)
+		output.Flush()
+		if status != nil {
+			status.End(err == nil)
+		}
+		if err != nil {
+			return fmt.Errorf("'%s' step from %s failed: %v", stepName(s), p.origin, err)
+		}
+	}
+	return nil
+}
+
+// followLogs copies what the container outputs to its logs from now on to the specified writer until the returned
+// function is called
+func followLogs(podName string, out io.Writer) (stop func()) {
+	since := v1.Now()
+	stream, err := k8s.GetClient().LogStream(podName, &corev1.PodLogOptions{Follow: true, SinceTime: &since})
+	if err != nil {
+		log.Errorf("Couldn't follow build output: %v", err)
+		return func() {}
+	}
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(out, stream)
+		close(done)
+	}()
+	return func() {
+		// give the cluster a chance to send the last lines before closing the stream
+		select {
+		case <-done:
+		case <-time.After(logsGracePeriod):
+		}
+		_ = stream.Close()
+	}
+}
+
+func stepName(s step) string {
+	if len(s.Name) > 0 {
+		return s.Name
+	}
+	return strings.Join(s.Command, " ")
+}
@@ -0,0 +1,196 @@
+package component
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/api/component/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/k8s"
+	"halkyon.io/hal/pkg/log"
+	"io/ioutil"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	portForwardCommandName = "port-forward"
+	// reconnectDelay controls how long we wait before trying to forward ports again after the connection to the pod was
+	// lost, e.g. because it was restarted
+	reconnectDelay = 2 * time.Second
+	// maxReconnectAttempts controls how many times in a row we try to forward ports without success before giving up
+	maxReconnectAttempts = 30
+)
+
+type portForwardOptions struct {
+	*cmdutil.ComponentTargetingOptions
+	component *v1beta1.Component
+	localPort int
+	ports     []string
+	mappings  []string
+}
+
+var (
+	portForwardExample = ktemplates.Examples(`  # Forward local port 8080 to the port of the current component
+  %[1]s
+
+  # Forward local port 9000 to the port of the backend-sb component
+  %[1]s -c backend-sb --local-port 9000
+
+  # Also forward local port 5005 to port 5005 of the current component, e.g. to attach a debugger
+  %[1]s -p 5005:5005`)
+)
+
+func (o *portForwardOptions) SetTargetingOptions(options *cmdutil.ComponentTargetingOptions) {
+	o.ComponentTargetingOptions = options
+}
+
+func (o *portForwardOptions) Complete(name string, cmd *cobra.Command, args []string) (err error) {
+	if o.component != nil {
+		return fmt.Errorf("ports can only be forwarded to a single component")
+	}
+	o.component, err = Entity.GetTyped(o.GetTargetedComponentName())
+	if err != nil {
+		return err
+	}
+
+	localPort := o.localPort
+	if localPort == 0 {
+		localPort = int(o.component.Spec.Port)
+	}
+	o.mappings = append(o.mappings, fmt.Sprintf("%d:%d", localPort, o.component.Spec.Port))
+	for _, port := range o.ports {
+		// a single port means using the same port locally and remotely
+		if !strings.Contains(port, ":") {
+			port = port + ":" + port
+		}
+		o.mappings = append(o.mappings, port)
+	}
+	return nil
+}
+
+func (o *portForwardOptions) Validate() error {
+	if o.component.Spec.Port == 0 {
+		return fmt.Errorf("'%s' component doesn't define any port", o.component.Name)
+	}
+	for _, mapping := range o.mappings {
+		for _, port := range strings.Split(mapping, ":") {
+			if p, err := strconv.Atoi(port); err != nil || p < 0 || p > 65535 {
+				return fmt.Errorf("invalid port mapping %s, expected 'local:remote' or 'port'", mapping)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *portForwardOptions) Run() error {
+	stop, cleanup := stopOnInterrupt()
+	defer cleanup()
+	return forwardPorts(o.component.Name, o.mappings, stop, func(podName string) {
+		log.Successf("Forwarding %s to '%s' component using '%s' pod, press Ctrl+C to stop", strings.Join(o.mappings, ", "), o.component.Name, podName)
+	})
+}
+
+// stopOnInterrupt returns a channel that is closed when the process is interrupted, along with a function to call to
+// stop listening to interruptions
+func stopOnInterrupt() (stop chan struct{}, cleanup func()) {
+	stop = make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		if _, ok := <-signals; ok {
+			close(stop)
+		}
+	}()
+	return stop, func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
+// forwardPorts keeps forwarding the specified port mappings to the named component's pod until the stop channel is
+// closed, re-resolving the pod whenever the connection to it is lost. The specified function is called each time ports
+// are successfully forwarded.
+func forwardPorts(componentName string, mappings []string, stop chan struct{}, onReady func(podName string)) error {
+	failures := 0
+	for {
+		podName, err := getPodName(componentName)
+		if err == nil {
+			ready := make(chan struct{})
+			go func() {
+				select {
+				case <-ready:
+					onReady(podName)
+				case <-stop:
+				}
+			}()
+			err = k8s.GetClient().PortForward(podName, mappings, stop, ready, ioutil.Discard, ioutil.Discard)
+			select {
+			case <-ready:
+				failures = 0
+			default:
+				// no point in trying again if local ports are already used
+				if err != nil && strings.Contains(err.Error(), "Unable to listen") {
+					return err
+				}
+				failures++
+			}
+		} else {
+			failures++
+		}
+
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+		if failures >= maxReconnectAttempts {
+			return fmt.Errorf("couldn't forward ports to '%s' component: %v", componentName, err)
+		}
+		if failures <= 1 {
+			if err == nil {
+				err = fmt.Errorf("lost connection to pod")
+			}
+			log.Errorf("%v, trying again…", err)
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// getPodName retrieves the pod currently associated with the named component, which changes when the pod is restarted
+func getPodName(componentName string) (string, error) {
+	c, err := Entity.GetTyped(componentName)
+	if err != nil {
+		return "", err
+	}
+	podName := c.Status.GetAssociatedPodName()
+	if len(podName) == 0 {
+		return "", fmt.Errorf("no pod is currently associated with '%s' component", c.Name)
+	}
+	return podName, nil
+}
+
+func NewCmdPortForward(fullParentName string) *cobra.Command {
+	o := &portForwardOptions{}
+	pf := &cobra.Command{
+		Use:   fmt.Sprintf("%s [flags]", portForwardCommandName),
+		Short: "Forward local ports to the component's pod",
+		Long: `Forward local ports to the component's pod, by default using the component's port both locally and remotely.
+
+Forwarding survives pod restarts, e.g. after a push, the pod being resolved again whenever the connection is lost.`,
+		Example: fmt.Sprintf(portForwardExample, cmdutil.CommandName(portForwardCommandName, fullParentName)),
+		Args:    cobra.NoArgs,
+	}
+	cmdutil.ConfigureRunnableAndCommandWithTargeting(o, pf)
+	pf.Flags().IntVar(&o.localPort, "local-port", 0, "Local port to forward to the component's port, defaults to the component's port")
+	pf.Flags().StringSliceVarP(&o.ports, "port", "p", nil, "Additional port mappings to forward, as 'local:remote' pairs or single ports used both locally and remotely")
+	return pf
+}
@@ -1,31 +1,41 @@
 package component
 
 import (
-	"bufio"
-	"crypto/sha1"
 	"fmt"
-	"github.com/mholt/archiver"
 	"github.com/spf13/cobra"
 	component "halkyon.io/api/component/v1beta1"
 	"halkyon.io/api/v1beta1"
 	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/ignore"
 	"halkyon.io/hal/pkg/k8s"
 	"halkyon.io/hal/pkg/log"
 	"io"
-	"io/ioutil"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ktemplates "k8s.io/kubectl/pkg/util/templates"
 	"os"
+	"path"
 	"path/filepath"
-	"strings"
+	"time"
 )
 
 const pushCommandName = "push"
 
 type pushOptions struct {
 	*cmdutil.ComponentTargetingOptions
-	binary bool
+	binary   bool
+	build    bool
+	artifact string
+	watch    bool
+	verbose  bool
+	debounce time.Duration
+	jobs     int
+	// out receives the output related to the targeted component when several components are pushed concurrently, the
+	// standard output being used otherwise
+	out io.Writer
+	// keepDescriptor records whether the local descriptor should be left untouched, e.g. when pushing a component whose
+	// spec was temporarily changed
+	keepDescriptor bool
 }
 
 func (o *pushOptions) SetTargetingOptions(options *cmdutil.ComponentTargetingOptions) {
@@ -34,137 +44,193 @@ func (o *pushOptions) SetTargetingOptions(options *cmdutil.ComponentTargetingOpt
 
 var (
 	pushExample = ktemplates.Examples(`  # Deploy the components client-sb, backend-sb
-  %[1]s -c client-sb,backend-sb`)
-	excludedFileNames = map[string]bool{
-		"target": true,
-	}
+  %[1]s -c client-sb,backend-sb
+
+  # Deploy the components client-sb, backend-sb and gateway, pushing at most 2 of them concurrently
+  %[1]s -c client-sb,backend-sb,gateway --jobs 2
+
+  # Deploy all the components listed in the hal-project.yml project file found in the current directory or its parents
+  %[1]s --all
+
+  # Keep watching the current component and push it again whenever local changes are detected
+  %[1]s --watch
+
+  # Push the current component, displaying the build output as it is produced
+  %[1]s --verbose
+
+  # Push the specified packaged binary of the current component
+  %[1]s --artifact target/app-runner.jar
+
+  # Package the current component using its Maven or Gradle build then push the resulting binary
+  %[1]s --binary --build`)
+	// defaultIgnoredPatterns lists patterns that are ignored when pushing unless negated in one of the ignore files
+	defaultIgnoredPatterns = []string{".git/", "target/"}
+	// ignoreFileNames lists the ignore files read when pushing, rules from later files take precedence
+	ignoreFileNames = []string{".gitignore", ".halignore"}
 )
 
 func (o *pushOptions) Complete(name string, cmd *cobra.Command, args []string) error {
+	if len(o.artifact) > 0 {
+		// targets are resolved before completing each of them, whether they're specified using -c or --all
+		if len(o.Targets()) > 1 {
+			return fmt.Errorf("--artifact can only be used when pushing a single component")
+		}
+		o.binary = true
+	}
 	return nil
 }
 
 func (o *pushOptions) Validate() (err error) {
-	return nil
+	if o.jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1, got %d", o.jobs)
+	}
+	if o.build {
+		if !o.binary {
+			return fmt.Errorf("--build can only be used when pushing a packaged binary")
+		}
+		if o.watch {
+			return fmt.Errorf("--build cannot be used in watch mode, which pushes again whenever the binary is rebuilt")
+		}
+	}
+	if len(o.artifact) > 0 {
+		_, err = artifactAt(o.artifact)
+	}
+	return err
 }
 
 func (o *pushOptions) Run() error {
+	_, err := o.pushTarget()
+	return err
+}
+
+// pushTarget pushes the targeted component if it changed, reporting whether it was pushed or skipped
+func (o *pushOptions) pushTarget() (pushResult, error) {
 	// first check that the component exists:
 	name := o.GetTargetedComponentName()
 	comp, err := Entity.GetTyped(name)
 	if err != nil {
 		// check error to see if it means that the component doesn't exist yet
 		if errors.IsNotFound(err) {
-			return fmt.Errorf("no component named '%s' exists, please create it first", name)
+			return pushFailed, fmt.Errorf("no component named '%s' exists, please create it first", name)
 		} else {
-			return err
+			return pushFailed, err
 		}
 	}
 
-	// check if the component revision is different
-	binaryPath, err := o.getComponentBinaryPath()
+	pipeline, err := o.resolvePipeline(comp)
 	if err != nil {
-		return fmt.Errorf("couldn't find binary to push: %s", binaryPath)
+		return pushFailed, err
 	}
-	if !o.binary {
-		// first check execution context: if we're executing in the component's directory, we don't need to prepend it to the file
-		currentDir, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-		isInComponentDir := filepath.Base(currentDir) == o.GetTargetedComponentName()
 
-		// generate tar
-		children, err := ioutil.ReadDir(o.GetTargetedComponentPath())
-		toTar := make([]string, 0, len(children))
+	// check if the component revision is different
+	var manifest sourceManifest
+	var binary artifact
+	var revision string
+	if o.binary {
+		if o.build {
+			if err = o.buildLocally(); err != nil {
+				return pushFailed, err
+			}
+		}
+		binary, err = o.resolveArtifact()
 		if err != nil {
-			return err
+			return pushFailed, fmt.Errorf("couldn't find binary to push: %v", err)
 		}
-		for _, child := range children {
-			name := child.Name()
-			if !strings.HasPrefix(name, ".") && !excludedFileNames[name] {
-				fileName := name
-				if !isInComponentDir {
-					fileName = filepath.Join(o.GetTargetedComponentName(), name)
-				}
-				toTar = append(toTar, fileName)
-			}
+		o.infof("Using %s", binary)
+		revision, err = binary.digest()
+		if err != nil {
+			return pushFailed, err
 		}
-
-		// create tar file
-		tar := archiver.NewTar()
-		tar.OverwriteExisting = true
-		if err := tar.Archive(toTar, binaryPath); err != nil {
-			return err
+	} else {
+		manifest, err = o.computeManifest()
+		if err != nil {
+			return pushFailed, err
 		}
-		defer os.Remove(binaryPath)
+		revision = manifest.revision()
 	}
-	file, err := os.Open(binaryPath)
-	if err != nil {
-		return err
-	}
-	input := bufio.NewReader(file)
-	hash := sha1.New()
-	if _, err := io.Copy(hash, input); err != nil {
-		return err
-	}
-	revision := fmt.Sprintf("%x", hash.Sum(nil))
-	if !o.needsPush(revision, comp) {
-		log.Infof("No local changes detected for '%s' component: nothing to push!", name)
-		return nil
+	if !o.needsPush(revision, comp, pipeline) {
+		o.infof("No local changes detected for '%s' component: nothing to push!", name)
+		return pushSkipped, nil
 	}
 	pushType := "source code"
 	if o.binary {
 		pushType = "packaged binary"
 	}
-	log.Infof("Local changes detected for '%s' component: about to push %s to remote cluster", name, pushType)
+	o.infof("Local changes detected for '%s' component: about to push %s to remote cluster", name, pushType)
 
-	err = o.push(comp)
+	err = o.push(comp, pipeline, manifest, binary, revision)
 	if err != nil {
-		return err
+		return pushFailed, err
 	}
 
-	// update the component revision
+	// update the component revision, recording which files were pushed so that we can only send changes next time
 	patch := fmt.Sprintf(`{"spec":{"revision":"%s"}}`, revision)
+	if !o.binary {
+		encoded, err := manifest.encode()
+		switch err {
+		case nil:
+			patch = fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}},"spec":{"revision":"%s"}}`, SourceManifestAnnotation, encoded, revision)
+		case errManifestTooLarge:
+			// remove any outdated manifest so that all files are pushed next time
+			o.infof("'%s' component has too many files to record which ones were pushed, all files will be pushed next time", name)
+			patch = fmt.Sprintf(`{"metadata":{"annotations":{"%s":null}},"spec":{"revision":"%s"}}`, SourceManifestAnnotation, revision)
+		default:
+			return pushFailed, err
+		}
+	}
 	comp.Spec.Revision = revision
-	_, err = Entity.client.Patch(name, types.MergePatchType, []byte(patch))
+	_, err = Entity.components().Patch(name, types.MergePatchType, []byte(patch))
 	if err != nil {
-		return err
+		return pushFailed, err
 	}
 
-	/// create or update halkyon descriptor
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return err
-	}
-	// if we're already in the component's dir, use that, otherwise use child directory if we're dealing with a component
-	componentDir := currentDir
-	if filepath.Base(currentDir) != comp.Name {
-		componentDir = filepath.Join(currentDir, comp.Name)
+	if o.keepDescriptor {
+		return pushSucceeded, nil
 	}
-	// remove Status
+
+	/// create or update halkyon descriptor
+	componentDir := o.GetTargetedComponentPath()
+	// remove Status and push state
 	comp.Status = component.ComponentStatus{}
-	comp.TypeMeta = typeMeta()
+	delete(comp.Annotations, SourceManifestAnnotation)
+	comp.TypeMeta = TypeMeta()
+	// components might share a descriptor so serialize updates when pushing them concurrently
+	descriptorLock.Lock()
+	defer descriptorLock.Unlock()
 	err = cmdutil.CreateOrUpdateHalkyonDescriptorWith(comp, componentDir)
 	if err != nil {
-		return err
+		return pushFailed, err
 	}
 
-	return nil
+	return pushSucceeded, nil
 }
 
-func (o *pushOptions) needsPush(revision string, c *component.Component) bool {
+// walkPushable walks the files of the targeted component that need to be pushed, honoring the rules defined in its ignore
+// files
+func (o *pushOptions) walkPushable(walkFn func(path, relative string, info os.FileInfo) error) error {
+	defaults := append([]string{"/" + o.GetTargetedComponentName() + ".tar"}, defaultIgnoredPatterns...)
+	return ignore.NewMatcher(o.GetTargetedComponentPath(), defaults, ignoreFileNames...).Walk(walkFn)
+}
+
+func (o *pushOptions) needsPush(revision string, c *component.Component, pipeline pushPipeline) bool {
 	sameRevision := revision == c.Spec.Revision
 	if !sameRevision {
 		return true
 	}
 
 	podName := c.Status.GetAssociatedPodName()
-	// todo: review if we still need to call IsJarPresent (and if logic needs to change)
-	return len(podName) > 0 && !k8s.IsJarPresent(podName)
+	if len(podName) == 0 {
+		return false
+	}
+	if !o.binary {
+		// the pod might have been restarted since the last push, in which case the pushed sources are gone
+		return getRemoteRevision(podName, pipeline) != c.Spec.Revision
+	}
+	// todo: review if we still need to check that the binary directory exists (and if logic needs to change)
+	return !k8s.GetClient().IsPresent(podName, pipeline.BinaryDir)
 }
 
-func (o *pushOptions) push(component *component.Component) error {
+func (o *pushOptions) push(component *component.Component, pipeline pushPipeline, manifest sourceManifest, binary artifact, revision string) error {
 	// wait for component to be ready
 	cp, err := o.waitUntilReady(component)
 	if err != nil {
@@ -173,66 +239,127 @@ func (o *pushOptions) push(component *component.Component) error {
 
 	c := k8s.GetClient()
 	podName := cp.Status.GetAssociatedPodName()
-	toPush, _ := o.getComponentBinaryPath()
-	s := log.Spinner("Uploading " + toPush)
-	defer s.End(false)
-	err = k8s.Copy(toPush, c.Namespace, podName, !o.binary)
-	if err != nil {
-		return fmt.Errorf("error uploading file: %v", err)
+	toPush := binary.path
+	if !o.binary {
+		toPush = o.sourceArchivePath()
 	}
-	s.End(true)
 
 	if !o.binary {
-		// clean up any existing code to avoid getting remnants from all code
-		if err = c.ExecCommand(podName, []string{"rm", "-rf", k8s.ExtractedSourcePathInContainer + "/*"},
-			"Cleaning up component"); err != nil {
+		// only send what changed if the pod still holds the sources we previously pushed
+		var previous sourceManifest
+		if encoded, ok := cp.Annotations[SourceManifestAnnotation]; ok && getRemoteRevision(podName, pipeline) == cp.Spec.Revision {
+			previous, err = decodeManifest(encoded)
+			if err != nil {
+				o.errorf("Ignoring invalid manifest of previously pushed files: %v", err)
+			}
+		}
+		changed, deleted := manifest.diff(previous)
+		if err := o.archive(changed, toPush); err != nil {
 			return err
 		}
+		defer os.Remove(toPush)
 
-		if err = c.ExecCommand(podName, []string{"tar", "xmf", k8s.SourcePathInContainer, "-C", k8s.ExtractedSourcePathInContainer},
-			"Extracting source on the remote cluster"); err != nil {
-			return err
+		if previous == nil {
+			// clean up any existing code to avoid getting remnants from all code
+			if err = o.runSteps(podName, pipeline, append([]step{pipeline.cleanUp()}, pipeline.PreCopy...)); err != nil {
+				return err
+			}
+		} else {
+			o.infof("Synchronizing %d added or modified and %d deleted file(s)", len(changed), len(deleted))
+			if len(deleted) > 0 {
+				toDelete := make([]string, 0, len(deleted)+2)
+				toDelete = append(toDelete, "rm", "-f")
+				for _, file := range deleted {
+					toDelete = append(toDelete, path.Join(pipeline.SourceDir, file))
+				}
+				s := o.spinner("Removing deleted files")
+				err = c.ExecCommand(podName, toDelete, "")
+				s.End(err == nil)
+				if err != nil {
+					return err
+				}
+			}
 		}
+	}
+
+	s := o.spinner("Uploading " + toPush)
+	defer s.End(false)
+	if o.binary {
+		err = c.Copy(toPush, podName, pipeline.BinaryDir)
+	} else {
+		err = c.ExtractFile(toPush, podName, pipeline.SourceDir)
+	}
+	if err != nil {
+		return fmt.Errorf("error uploading file: %v", err)
+	}
+	s.End(true)
 
-		if err = c.ExecCommand(podName, []string{"/var/lib/supervisord/bin/supervisord", "ctl", "start", "build"}, "Performing build"); err != nil {
+	if !o.binary {
+		if err = setRemoteRevision(podName, revision, pipeline); err != nil {
 			return err
 		}
-
-		if err = c.ExecCommand(podName, []string{"bash", "-c", "while /var/lib/supervisord/bin/supervisord ctl status build | grep RUNNING; do sleep 1; done"},
-			"Waiting for build to finish"); err != nil {
+		output := log.NewBuildOutput(buildOutputTail, o.echo())
+		if err = o.buildRemotely(podName, pipeline, output); err != nil {
+			if !o.verbose {
+				o.errorf("Build of '%s' component failed, last lines of its output:", component.Name)
+				output.PrintTail(o.stderr())
+			}
 			return err
 		}
 	}
 
-	if err = c.ExecCommand(podName, []string{"/var/lib/supervisord/bin/supervisord", "ctl", "stop", "run"}, ""); err != nil {
+	if err = o.runSteps(podName, pipeline, pipeline.Restart); err != nil {
 		return err
 	}
-	if err = c.ExecCommand(podName, []string{"/var/lib/supervisord/bin/supervisord", "ctl", "start", "run"}, "Restarting app"); err != nil {
-		return err
+	o.successf("Successfully pushed '%s' component to remote cluster", component.Name)
+	return nil
+}
+
+// buildRemotely runs the build phases of the specified pipeline, sending the build output to the specified BuildOutput
+func (o *pushOptions) buildRemotely(podName string, pipeline pushPipeline, output *log.BuildOutput) error {
+	if pipeline.BuildOutputInLogs {
+		stop := followLogs(podName, output)
+		defer stop()
+	}
+	for _, steps := range [][]step{pipeline.Build, pipeline.Wait, pipeline.Check} {
+		if err := o.run(pipeline, podName, steps, output); err != nil {
+			return err
+		}
 	}
-	log.Successf("Successfully pushed '%s' component to remote cluster", component.Name)
 	return nil
 }
 
-func (o *pushOptions) getComponentBinaryPath() (string, error) {
-	if !o.binary {
-		currentDir, _ := os.Getwd()
-		return filepath.Join(currentDir, o.GetTargetedComponentName()+".tar"), nil
+// runSteps runs the specified non-build steps, displaying the tail of their output if they fail
+func (o *pushOptions) runSteps(podName string, pipeline pushPipeline, steps []step) error {
+	output := log.NewBuildOutput(buildOutputTail, o.echo())
+	err := o.run(pipeline, podName, steps, output)
+	if err != nil && !o.verbose {
+		output.PrintTail(o.stderr())
 	}
+	return err
+}
 
-	target := filepath.Join(o.GetTargetedComponentPath(), "target")
-	files, err := ioutil.ReadDir(target)
-	if err != nil {
-		return target + " directory not found or unreadable", err
+// echo returns where steps' output should be echoed as it is produced, if at all
+func (o *pushOptions) echo() io.Writer {
+	if o.verbose {
+		return o.stdout()
 	}
+	return nil
+}
 
-	for _, file := range files {
-		name := file.Name()
-		if strings.HasSuffix(name, ".jar") {
-			return filepath.Join(target, name), nil
-		}
+// sourceArchivePath returns the path of the archive in which the targeted component's sources are packaged to be pushed
+func (o *pushOptions) sourceArchivePath() string {
+	currentDir, _ := os.Getwd()
+	return filepath.Join(currentDir, o.GetTargetedComponentName()+".tar")
+}
+
+// resolveArtifact returns the artifact to push in binary mode, either as explicitly specified or as found in the
+// targeted component's build directories
+func (o *pushOptions) resolveArtifact() (artifact, error) {
+	if len(o.artifact) > 0 {
+		return artifactAt(o.artifact)
 	}
-	return "no jar file found in " + target, nil
+	return findArtifact(o.GetTargetedComponentPath())
 }
 
 func (o *pushOptions) waitUntilReady(c *component.Component) (*component.Component, error) {
@@ -242,9 +369,11 @@ func (o *pushOptions) waitUntilReady(c *component.Component) (*component.Compone
 
 	name := o.GetTargetedComponentName()
 	client := k8s.GetClient()
-	cp, err := client.WaitForComponent(name, component.PushReady, "Waiting for component "+name+" to be ready…")
+	s := o.spinner("Waiting for component " + name + " to be ready…")
+	cp, err := client.WaitForComponent(name, component.PushReady, "")
+	s.End(err == nil)
 	if err != nil {
-		return nil, fmt.Errorf("error waiting for component: %v", err)
+		return nil, fmt.Errorf("error waiting for component: %v, use 'describe' to find out more about '%s' component's status", err, name)
 	}
 	err = errorIfFailedOrUnknown(c)
 	if err != nil {
@@ -264,14 +393,47 @@ func errorIfFailedOrUnknown(c *component.Component) error {
 
 func NewCmdPush(fullParentName string) *cobra.Command {
 	push := &cobra.Command{
-		Use:     fmt.Sprintf("%s [flags]", pushCommandName),
-		Short:   "Push a local project to the remote cluster you're connected to",
-		Long:    `Push a local project to the remote cluster you're connected to.`,
+		Use:   fmt.Sprintf("%s [flags]", pushCommandName),
+		Short: "Push a local project to the remote cluster you're connected to",
+		Long: `Push a local project to the remote cluster you're connected to.
+
+How pushed content is deployed is defined by a pipeline read from the 'hal.halkyon.io/push-pipeline' annotation of the
+component in its local halkyon descriptor or, if not set there, of its runtime, for example:
+
+  sourceDir: /usr/src
+  build:
+  - name: Installing dependencies
+    command: [npm, install]
+  restart:
+  - name: Restarting app
+    command: [sh, -c, "pkill node; nohup npm start &"]
+
+Besides build, the preCopy, wait and check phases can be defined, a failing check marking the build as failed. The
+preCopy phase is executed before all sources are pushed, after the source directory was emptied. Pipelines running
+their build in the background can set buildOutputInLogs so that the container's logs are followed while building.
+
+Runtimes without pipeline are assumed to use supervisord to build and run the application.
+
+In binary mode, the artifact to push is looked for in the target, build and build/libs directories of the component,
+in this order of precedence: Quarkus fast-jar directory (quarkus-app), native executable (*-runner), jar then war file.
+Sources, javadoc and tests jars are ignored. Use --artifact to push a specific file or directory instead.
+
+When targeting several components, they are pushed concurrently, up to --jobs at a time, their output being prefixed
+with their name. A failing component doesn't prevent the others from being pushed and a summary is displayed at the end.
+
+If the build fails, the last lines of its output are displayed with errors highlighted. Use --verbose to see the
+complete output as it is produced.`,
 		Example: fmt.Sprintf(pushExample, cmdutil.CommandName(pushCommandName, fullParentName)),
 		Args:    cobra.NoArgs,
 	}
 	options := pushOptions{}
 	cmdutil.ConfigureRunnableAndCommandWithTargeting(&options, push)
 	push.Flags().BoolVarP(&options.binary, "binary", "b", false, "Push packaged binary instead of source code")
+	push.Flags().BoolVar(&options.build, "build", false, "Package the component locally using its Maven or Gradle build before pushing the binary")
+	push.Flags().StringVar(&options.artifact, "artifact", "", "Path of the packaged binary to push, implies --binary")
+	push.Flags().BoolVarP(&options.watch, "watch", "w", false, "Keep watching the component(s) and push again whenever local changes are detected")
+	push.Flags().BoolVar(&options.verbose, "verbose", false, "Stream the output of the build and other remote commands as they execute")
+	push.Flags().IntVarP(&options.jobs, "jobs", "j", 4, "Maximum number of components pushed concurrently when targeting several components")
+	push.Flags().DurationVar(&options.debounce, "debounce", time.Second, "How long changes need to settle down before pushing again in watch mode")
 	return push
 }
@@ -0,0 +1,198 @@
+package component
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"halkyon.io/hal/pkg/k8s"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// SourceManifestAnnotation records, on the component, the manifest of the source files that were last pushed
+	SourceManifestAnnotation = "hal.halkyon.io/source-manifest"
+	// maxEncodedManifestSize bounds the size of the manifest annotation, the annotations of an object being limited to
+	// 256KiB in total
+	maxEncodedManifestSize = 128 * 1024
+)
+
+// errManifestTooLarge is returned when a manifest records too many files to be stored in an annotation
+var errManifestTooLarge = errors.New("source manifest is too large to be recorded")
+
+// sourceManifest associates the slash-separated path, relative to the component's directory, of each pushable file with
+// the SHA1 of its content
+type sourceManifest map[string]string
+
+// computeManifest hashes every pushable file of the targeted component
+func (o *pushOptions) computeManifest() (sourceManifest, error) {
+	manifest := make(sourceManifest, 101)
+	err := o.walkPushable(func(path, relative string, info os.FileInfo) error {
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		manifest[relative] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha1.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func (m sourceManifest) sortedPaths() []string {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// revision computes a revision identifying the whole set of files recorded by this manifest
+func (m sourceManifest) revision() string {
+	hash := sha1.New()
+	for _, path := range m.sortedPaths() {
+		_, _ = fmt.Fprintf(hash, "%s %s\n", m[path], path)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// diff returns which files need to be sent (because they were added or modified) and which need to be deleted for the
+// remote tree to match this manifest, assuming the remote tree matches the previous one. A nil previous manifest
+// results in all files needing to be sent.
+func (m sourceManifest) diff(previous sourceManifest) (changed []string, deleted []string) {
+	changed = make([]string, 0, len(m))
+	for _, path := range m.sortedPaths() {
+		if previousHash, ok := previous[path]; !ok || previousHash != m[path] {
+			changed = append(changed, path)
+		}
+	}
+	deleted = make([]string, 0, 7)
+	for _, path := range previous.sortedPaths() {
+		if _, ok := m[path]; !ok {
+			deleted = append(deleted, path)
+		}
+	}
+	return changed, deleted
+}
+
+// encode compresses the manifest so that it can be stored in an annotation, returning errManifestTooLarge if it cannot
+func (m sourceManifest) encode() (string, error) {
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(raw); err != nil {
+		return "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	encoded := base64.StdEncoding.EncodeToString(compressed.Bytes())
+	if len(encoded) > maxEncodedManifestSize {
+		return "", errManifestTooLarge
+	}
+	return encoded, nil
+}
+
+func decodeManifest(encoded string) (sourceManifest, error) {
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	manifest := sourceManifest{}
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// archive creates a tar file at the specified target containing the specified files, identified by their path relative
+// to the targeted component's directory
+func (o *pushOptions) archive(files []string, target string) error {
+	out, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	writer := tar.NewWriter(out)
+	for _, file := range files {
+		if err := addToArchive(writer, filepath.Join(o.GetTargetedComponentPath(), filepath.FromSlash(file)), file); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+func addToArchive(writer *tar.Writer, path, name string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := writer.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(writer, file)
+	return err
+}
+
+// getRemoteRevision retrieves the revision of the sources currently extracted in the specified pod, if any
+func getRemoteRevision(podName string, pipeline pushPipeline) string {
+	var out bytes.Buffer
+	if err := k8s.GetClient().ExecCMDInContainer(podName, []string{"cat", pipeline.revisionMarker()}, &out, nil, nil, false); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out.String())
+}
+
+func setRemoteRevision(podName, revision string, pipeline pushPipeline) error {
+	return k8s.GetClient().ExecCommand(podName, []string{"sh", "-c", fmt.Sprintf("echo %s > %s", revision, pipeline.revisionMarker())}, "")
+}
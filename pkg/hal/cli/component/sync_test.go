@@ -0,0 +1,79 @@
+package component
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestManifestDiff(t *testing.T) {
+	current := sourceManifest{"pom.xml": "1", "src/App.java": "2", "src/New.java": "3"}
+	tests := []struct {
+		name     string
+		previous sourceManifest
+		changed  []string
+		deleted  []string
+	}{
+		{
+			name:    "first push",
+			changed: []string{"pom.xml", "src/App.java", "src/New.java"},
+			deleted: []string{},
+		},
+		{
+			name:     "unchanged",
+			previous: sourceManifest{"pom.xml": "1", "src/App.java": "2", "src/New.java": "3"},
+			changed:  []string{},
+			deleted:  []string{},
+		},
+		{
+			name:     "added, modified and deleted",
+			previous: sourceManifest{"pom.xml": "1", "src/App.java": "0", "src/Old.java": "4", ".mvn/config": "5"},
+			changed:  []string{"src/App.java", "src/New.java"},
+			deleted:  []string{".mvn/config", "src/Old.java"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed, deleted := current.diff(tt.previous)
+			if !reflect.DeepEqual(changed, tt.changed) {
+				t.Errorf("expected changed files %v, got %v", tt.changed, changed)
+			}
+			if !reflect.DeepEqual(deleted, tt.deleted) {
+				t.Errorf("expected deleted files %v, got %v", tt.deleted, deleted)
+			}
+		})
+	}
+}
+
+func TestManifestEncoding(t *testing.T) {
+	manifest := sourceManifest{"pom.xml": "1", "src/main/java/App.java": "2", ".mvn/wrapper/maven-wrapper.properties": "3"}
+	encoded, err := manifest.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decodeManifest(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, manifest) {
+		t.Errorf("expected %v, got %v", manifest, decoded)
+	}
+	if decoded.revision() != manifest.revision() {
+		t.Errorf("revision changed after decoding")
+	}
+
+	if _, err := decodeManifest("not a manifest"); err == nil {
+		t.Errorf("expected invalid manifest to be rejected")
+	}
+}
+
+func TestManifestTooLarge(t *testing.T) {
+	manifest := make(sourceManifest, 10000)
+	for i := 0; i < 10000; i++ {
+		manifest[fmt.Sprintf("src/main/java/dev/snowdrop/File%d.java", i)] = fmt.Sprintf("%x", sha1.Sum([]byte{byte(i), byte(i >> 8)}))
+	}
+	if _, err := manifest.encode(); err != errManifestTooLarge {
+		t.Errorf("expected errManifestTooLarge, got %v", err)
+	}
+}
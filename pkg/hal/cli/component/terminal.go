@@ -0,0 +1,75 @@
+package component
+
+import (
+	"fmt"
+	"golang.org/x/crypto/ssh/terminal"
+	"k8s.io/client-go/tools/remotecommand"
+	"os"
+)
+
+// terminalSession puts the local terminal in raw mode and tracks its size so that a remote interactive session behaves
+// like a local one. It implements remotecommand.TerminalSizeQueue.
+type terminalSession struct {
+	in    int
+	out   int
+	state *terminal.State
+	sizes chan remotecommand.TerminalSize
+	done  chan struct{}
+}
+
+var _ remotecommand.TerminalSizeQueue = &terminalSession{}
+
+// startTerminalSession switches the terminal attached to the specified input and output to raw mode, Close needing to
+// be called to restore it
+func startTerminalSession(in, out *os.File) (*terminalSession, error) {
+	s := &terminalSession{
+		in:    int(in.Fd()),
+		out:   int(out.Fd()),
+		sizes: make(chan remotecommand.TerminalSize, 1),
+		done:  make(chan struct{}),
+	}
+	if !terminal.IsTerminal(s.in) || !terminal.IsTerminal(s.out) {
+		return nil, fmt.Errorf("a terminal is required to allocate a TTY")
+	}
+	state, err := terminal.MakeRaw(s.in)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't switch terminal to raw mode: %v", err)
+	}
+	s.state = state
+	s.sendSize()
+	s.watchResize()
+	return s, nil
+}
+
+// Next returns the next terminal size, blocking until it changes, or nil once the session is closed
+func (s *terminalSession) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-s.sizes:
+		return &size
+	case <-s.done:
+		return nil
+	}
+}
+
+// sendSize records the current size of the terminal, replacing any size that wasn't sent yet
+func (s *terminalSession) sendSize() {
+	width, height, err := terminal.GetSize(s.out)
+	if err != nil {
+		return
+	}
+	size := remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+	for {
+		select {
+		case s.sizes <- size:
+			return
+		case <-s.sizes:
+			// drop stale size
+		}
+	}
+}
+
+// Close restores the terminal to the state it was in before the session started
+func (s *terminalSession) Close() {
+	close(s.done)
+	_ = terminal.Restore(s.in, s.state)
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+// +build !windows
+
+package component
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchResize propagates terminal size changes, as notified by SIGWINCH, until the session is closed
+func (s *terminalSession) watchResize() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-signals:
+				s.sendSize()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
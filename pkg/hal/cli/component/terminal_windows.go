@@ -0,0 +1,33 @@
+//go:build windows
+// +build windows
+
+package component
+
+import (
+	"golang.org/x/crypto/ssh/terminal"
+	"time"
+)
+
+// resizePollInterval controls how often the console size is checked since Windows doesn't notify size changes
+const resizePollInterval = 250 * time.Millisecond
+
+// watchResize propagates console size changes, polling for them, until the session is closed
+func (s *terminalSession) watchResize() {
+	go func() {
+		width, height, _ := terminal.GetSize(s.out)
+		ticker := time.NewTicker(resizePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				w, h, err := terminal.GetSize(s.out)
+				if err == nil && (w != width || h != height) {
+					width, height = w, h
+					s.sendSize()
+				}
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,139 @@
+package component
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/log"
+	"os"
+	"time"
+)
+
+// watchPollInterval controls how often watched component directories are checked for changes
+const watchPollInterval = 500 * time.Millisecond
+
+type watchedComponent struct {
+	// fingerprint is empty when it couldn't be computed, e.g. because files were being modified while walking them
+	fingerprint string
+	changedAt   time.Time
+	dirty       bool
+	failing     bool
+}
+
+// update computes the current fingerprint of the targeted component's files, returning false if it couldn't be
+// computed, in which case the error is only reported once until the fingerprint can be computed again
+func (state *watchedComponent) update(o *pushOptions) (fingerprint string, ok bool) {
+	fingerprint, err := o.fingerprint()
+	if err != nil {
+		if !state.failing {
+			o.errorf("Couldn't check '%s' component for changes, trying again: %v", o.GetTargetedComponentName(), err)
+			state.failing = true
+		}
+		return "", false
+	}
+	state.failing = false
+	return fingerprint, true
+}
+
+var _ cmdutil.AllTargetsRunnable = &pushOptions{}
+
+func (o *pushOptions) RunOnAllTargets() error {
+	switch {
+	case o.watch:
+		return o.watchAndPush()
+	case len(o.Targets()) > 1:
+		return o.pushConcurrently()
+	default:
+		return o.ForEachTarget(o.Run)
+	}
+}
+
+// watchAndPush pushes all targeted components then keeps polling their directories, pushing them again once changes
+// have settled down for the configured debounce duration. Push errors are reported but don't stop the watch.
+func (o *pushOptions) watchAndPush() error {
+	watched := make(map[string]*watchedComponent, 7)
+	err := o.ForEachTarget(func() error {
+		o.pushAndReport()
+		state := &watchedComponent{}
+		state.fingerprint, _ = state.update(o)
+		watched[o.GetTargetedComponentPath()] = state
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Watching for local changes, press Ctrl+C to stop")
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		err := o.ForEachTarget(func() error {
+			state := watched[o.GetTargetedComponentPath()]
+			fingerprint, ok := state.update(o)
+			if !ok {
+				return nil
+			}
+			if len(state.fingerprint) == 0 {
+				// files were just pushed when the fingerprint couldn't be computed initially
+				state.fingerprint = fingerprint
+				return nil
+			}
+			if fingerprint != state.fingerprint {
+				// record change but wait for things to settle down before pushing
+				state.fingerprint = fingerprint
+				state.changedAt = time.Now()
+				state.dirty = true
+				return nil
+			}
+			if state.dirty && time.Since(state.changedAt) >= o.debounce {
+				state.dirty = false
+				o.pushAndReport()
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *pushOptions) pushAndReport() {
+	if err := o.Run(); err != nil {
+		log.Errorf("Couldn't push '%s' component: %v", o.GetTargetedComponentName(), err)
+	}
+}
+
+// fingerprint computes a cheap signature of the pushable content of the targeted component, based on file paths, sizes
+// and modification times, so that changes can be detected without hashing the files' content
+func (o *pushOptions) fingerprint() (string, error) {
+	hash := sha1.New()
+	record := func(path string, info os.FileInfo) {
+		_, _ = fmt.Fprintf(hash, "%s|%d|%d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+
+	if o.binary {
+		binary, err := o.resolveArtifact()
+		if err != nil {
+			// no binary yet, nothing to fingerprint
+			return "", nil
+		}
+		err = binary.walk(func(path, relative string, info os.FileInfo) error {
+			record(relative, info)
+			return nil
+		})
+		if err != nil {
+			return "", nil
+		}
+		return fmt.Sprintf("%x", hash.Sum(nil)), nil
+	}
+
+	err := o.walkPushable(func(path, relative string, info os.FileInfo) error {
+		record(relative, info)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
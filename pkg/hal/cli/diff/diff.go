@@ -0,0 +1,156 @@
+package diff
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/log"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"os"
+	"sort"
+)
+
+const commandName = "diff"
+
+type options struct {
+	file       string
+	descriptor *cmdutil.HalkyonDescriptor
+	// complete records whether the descriptor holds all the local entities, in which case entities only existing in
+	// the cluster are reported
+	complete bool
+}
+
+var (
+	diffExample = ktemplates.Examples(`  # Compare the components and capabilities defined in the current and child directories with their cluster version
+  %[1]s
+
+  # Compare the components and capabilities defined in the specified descriptor with their cluster version
+  %[1]s -f halkyon.yml`)
+)
+
+func (o *options) Complete(name string, cmd *cobra.Command, args []string) error {
+	if len(o.file) > 0 {
+		descriptor, err := cmdutil.LoadHalkyonDescriptor(o.file)
+		if err != nil {
+			return fmt.Errorf("couldn't load descriptor %s: %v", o.file, err)
+		}
+		o.descriptor = descriptor
+		return nil
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	o.descriptor = cmdutil.LoadAvailableHalkyonEntities(currentDir)
+	o.complete = true
+	return nil
+}
+
+func (o *options) Validate() error {
+	return nil
+}
+
+func (o *options) Run() error {
+	different := 0
+	for _, t := range cmdutil.KnownResourceTypes() {
+		client := cmdutil.EntityFor(t)
+		local := o.descriptor.GetDefinedEntitiesWith(t)
+
+		names := make(map[string]bool, len(local))
+		for name := range local {
+			names[name] = true
+		}
+		if o.complete {
+			// also consider entities only existing in the cluster
+			remote, err := client.List()
+			if err != nil {
+				return err
+			}
+			for _, entity := range remote {
+				if accessor, err := meta.Accessor(entity); err == nil {
+					names[accessor.GetName()] = true
+				}
+			}
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+
+		for _, name := range sorted {
+			entity, definedLocally := local[name]
+			if !definedLocally {
+				different++
+				log.Infof("%s '%s' only exists in the cluster", t, name)
+				continue
+			}
+			if diffEntity(t, client, entity) {
+				different++
+			}
+		}
+	}
+
+	if different == 0 {
+		log.Successf("Local descriptors and cluster are in sync")
+		return nil
+	}
+	// exit with status 1 like diff tools do so that differences can be detected by scripts
+	return cmdutil.ExitStatusError{Status: 1, Message: fmt.Sprintf("%d entities differ", different)}
+}
+
+// diffEntity outputs the differences between the specified local entity and its cluster version, returning whether
+// any were found
+func diffEntity(t cmdutil.ResourceType, client cmdutil.HalkyonEntity, entity cmdutil.HalkyonDescriptorEntity) bool {
+	remote, err := client.Get(entity.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Infof("%s '%s' only exists locally, in %s", t, entity.Name, entity.Path)
+		} else {
+			log.Errorf("Couldn't retrieve %s '%s': %v", t, entity.Name, err)
+		}
+		return true
+	}
+
+	localSpec, err := specOf(entity.Entity)
+	if err != nil {
+		log.Errorf("Couldn't read local %s '%s': %v", t, entity.Name, err)
+		return true
+	}
+	remoteSpec, err := specOf(remote)
+	if err != nil {
+		log.Errorf("Couldn't read cluster %s '%s': %v", t, entity.Name, err)
+		return true
+	}
+
+	differences := compare("spec", localSpec, remoteSpec)
+	if len(differences) == 0 {
+		return false
+	}
+	log.Infof("%s '%s' differs from its cluster version:", t, entity.Name)
+	for _, difference := range differences {
+		fmt.Fprintf(log.GetStdout(), "  %s\n", difference)
+	}
+	return true
+}
+
+func NewCmdDiff(parent string) *cobra.Command {
+	o := &options{}
+	diff := &cobra.Command{
+		Use:   fmt.Sprintf("%s [flags]", commandName),
+		Short: "Display the differences between local descriptors and the cluster state",
+		Long: `Display, field by field, how the specs of the components and capabilities defined in local descriptors differ from their cluster version, flagging entities that only exist on one side. Entities only existing in the cluster are not reported when comparing a single descriptor.
+
+The exit status is 0 when no differences are found and 1 otherwise, making it possible to detect drift in scripts.`,
+		Example: fmt.Sprintf(diffExample, cmdutil.CommandName(commandName, parent)),
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.GenericRun(o, cmd, args)
+		},
+	}
+	diff.Flags().StringVarP(&o.file, "file", "f", "", "Halkyon descriptor to compare instead of the ones found in the current and child directories")
+	return diff
+}
@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// fieldDifference records how the value at the specified path differs between the local and cluster versions of an
+// entity. A nil value means the field isn't set on that side.
+type fieldDifference struct {
+	path    string
+	local   interface{}
+	cluster interface{}
+}
+
+func (d fieldDifference) String() string {
+	return fmt.Sprintf("%s: %s (local) != %s (cluster)", d.path, display(d.local), display(d.cluster))
+}
+
+func display(value interface{}) string {
+	if value == nil {
+		return "<unset>"
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bytes)
+}
+
+// specOf retrieves the spec of the specified object as generic JSON values so that it can be compared field by field
+func specOf(object interface{}) (map[string]interface{}, error) {
+	bytes, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{}, 7)
+	if err := json.Unmarshal(bytes, &fields); err != nil {
+		return nil, err
+	}
+	spec, _ := fields["spec"].(map[string]interface{})
+	return spec, nil
+}
+
+// compare returns the differences between the local and cluster values, sorted by path. Empty values are considered
+// equivalent to unset ones since they are omitted from serialized descriptors.
+func compare(path string, local, cluster interface{}) []fieldDifference {
+	if isEmpty(local) && isEmpty(cluster) {
+		return nil
+	}
+
+	localMap, localIsMap := local.(map[string]interface{})
+	clusterMap, clusterIsMap := cluster.(map[string]interface{})
+	if (localIsMap || local == nil) && (clusterIsMap || cluster == nil) {
+		keys := make(map[string]bool, len(localMap)+len(clusterMap))
+		for key := range localMap {
+			keys[key] = true
+		}
+		for key := range clusterMap {
+			keys[key] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		differences := make([]fieldDifference, 0, 3)
+		for _, key := range sorted {
+			differences = append(differences, compare(join(path, key), localMap[key], clusterMap[key])...)
+		}
+		return differences
+	}
+
+	localSlice, localIsSlice := local.([]interface{})
+	clusterSlice, clusterIsSlice := cluster.([]interface{})
+	if localIsSlice && clusterIsSlice && len(localSlice) == len(clusterSlice) {
+		differences := make([]fieldDifference, 0, 3)
+		for i := range localSlice {
+			differences = append(differences, compare(fmt.Sprintf("%s[%d]", path, i), localSlice[i], clusterSlice[i])...)
+		}
+		return differences
+	}
+
+	if reflect.DeepEqual(local, cluster) {
+		return nil
+	}
+	if isEmpty(local) {
+		local = nil
+	}
+	if isEmpty(cluster) {
+		cluster = nil
+	}
+	return []fieldDifference{{path: path, local: local, cluster: cluster}}
+}
+
+func join(path, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+	return strings.Join([]string{path, key}, ".")
+}
+
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return len(v) == 0
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case map[string]interface{}:
+		for _, child := range v {
+			if !isEmpty(child) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
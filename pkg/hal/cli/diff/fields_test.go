@@ -0,0 +1,70 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name     string
+		local    string
+		cluster  string
+		expected []string
+	}{
+		{
+			name:    "identical",
+			local:   `{"runtime":"spring-boot","port":8080}`,
+			cluster: `{"runtime":"spring-boot","port":8080}`,
+		},
+		{
+			name:     "modified field",
+			local:    `{"runtime":"spring-boot","port":8080}`,
+			cluster:  `{"runtime":"spring-boot","port":9090}`,
+			expected: []string{"port: 8080 (local) != 9090 (cluster)"},
+		},
+		{
+			name:     "fields set on only one side",
+			local:    `{"runtime":"spring-boot"}`,
+			cluster:  `{"version":"2.1.6"}`,
+			expected: []string{`runtime: "spring-boot" (local) != <unset> (cluster)`, `version: <unset> (local) != "2.1.6" (cluster)`},
+		},
+		{
+			name:    "empty values are equivalent to unset ones",
+			local:   `{"runtime":"spring-boot"}`,
+			cluster: `{"runtime":"spring-boot","exposeService":false,"envs":[],"revision":"","capabilities":{}}`,
+		},
+		{
+			name:     "nested fields",
+			local:    `{"capabilities":{"requires":[{"name":"db","boundTo":""}]}}`,
+			cluster:  `{"capabilities":{"requires":[{"name":"db","boundTo":"postgres"}]}}`,
+			expected: []string{`capabilities.requires[0].boundTo: <unset> (local) != "postgres" (cluster)`},
+		},
+		{
+			name:     "lists with different sizes",
+			local:    `{"envs":[{"name":"A"}]}`,
+			cluster:  `{"envs":[{"name":"A"},{"name":"B"}]}`,
+			expected: []string{`envs: [{"name":"A"}] (local) != [{"name":"A"},{"name":"B"}] (cluster)`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var local, cluster interface{}
+			if err := json.Unmarshal([]byte(tt.local), &local); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.cluster), &cluster); err != nil {
+				t.Fatal(err)
+			}
+			differences := compare("", local, cluster)
+			if len(differences) != len(tt.expected) {
+				t.Fatalf("expected %d differences, got %v", len(tt.expected), differences)
+			}
+			for i, difference := range differences {
+				if difference.String() != tt.expected[i] {
+					t.Errorf("expected '%s', got '%s'", tt.expected[i], difference.String())
+				}
+			}
+		})
+	}
+}
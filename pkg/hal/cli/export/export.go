@@ -0,0 +1,194 @@
+package export
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	v1beta13 "halkyon.io/api/capability/v1beta1"
+	v1beta12 "halkyon.io/api/component/v1beta1"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/hal/cli/capability"
+	"halkyon.io/hal/pkg/hal/cli/component"
+	"halkyon.io/hal/pkg/log"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktemplates "k8s.io/kubectl/pkg/util/templates"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const commandName = "export"
+
+// ignoredAnnotations are annotations that record cluster state and therefore shouldn't be exported
+var ignoredAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	component.SourceManifestAnnotation,
+}
+
+type options struct {
+	names        []string
+	output       string
+	components   []*v1beta12.Component
+	capabilities []*v1beta13.Capability
+}
+
+var (
+	exportExample = ktemplates.Examples(`  # Export all the components and capabilities of the current namespace to descriptors in the current directory
+  %[1]s
+
+  # Export the client-sb and backend-sb components, along with the capabilities they are bound to, in the app directory
+  %[1]s -c client-sb,backend-sb -o app`)
+)
+
+func (o *options) Complete(name string, cmd *cobra.Command, args []string) error {
+	if len(o.output) == 0 {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		o.output = currentDir
+	}
+
+	if len(o.names) == 0 {
+		components, err := component.Entity.List()
+		if err != nil {
+			return err
+		}
+		for _, c := range components {
+			o.components = append(o.components, c.(*v1beta12.Component))
+		}
+		capabilities, err := capability.Entity.List()
+		if err != nil {
+			return err
+		}
+		for _, c := range capabilities {
+			o.capabilities = append(o.capabilities, c.(*v1beta13.Capability))
+		}
+		return nil
+	}
+
+	// only export the specified components and the capabilities they are bound to
+	bound := make(map[string]bool, len(o.names))
+	for _, name := range o.names {
+		c, err := component.Entity.GetTyped(name)
+		if err != nil {
+			return fmt.Errorf("couldn't retrieve '%s' component: %v", name, err)
+		}
+		o.components = append(o.components, c)
+		for _, required := range c.Spec.Capabilities.Requires {
+			if len(required.BoundTo) > 0 && !bound[required.BoundTo] {
+				bound[required.BoundTo] = true
+				c, err := capability.Entity.GetTyped(required.BoundTo)
+				if err != nil {
+					return fmt.Errorf("couldn't retrieve '%s' capability: %v", required.BoundTo, err)
+				}
+				o.capabilities = append(o.capabilities, c)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *options) Validate() error {
+	if len(o.components) == 0 && len(o.capabilities) == 0 {
+		return fmt.Errorf("no components or capabilities to export")
+	}
+	return nil
+}
+
+func (o *options) Run() error {
+	// capabilities only used by one exported component are exported along with it, others go in the root descriptor
+	users := make(map[string][]string, len(o.capabilities))
+	for _, c := range o.components {
+		for _, required := range c.Spec.Capabilities.Requires {
+			if len(required.BoundTo) > 0 {
+				users[required.BoundTo] = append(users[required.BoundTo], c.Name)
+			}
+		}
+	}
+
+	descriptors := make(map[string][]runtime.Object, len(o.components)+1)
+	for _, c := range o.components {
+		dir := filepath.Join(o.output, c.Name)
+		c.TypeMeta = component.TypeMeta()
+		c.ObjectMeta = cleanObjectMeta(c.ObjectMeta)
+		c.Status = v1beta12.ComponentStatus{}
+		// the revision identifies the sources last pushed to the cluster
+		c.Spec.Revision = ""
+		descriptors[dir] = append(descriptors[dir], c)
+	}
+	for _, c := range o.capabilities {
+		dir := o.output
+		if componentNames := users[c.Name]; len(componentNames) == 1 {
+			dir = filepath.Join(o.output, componentNames[0])
+		}
+		c.TypeMeta = capability.TypeMeta()
+		c.ObjectMeta = cleanObjectMeta(c.ObjectMeta)
+		c.Status = v1beta13.CapabilityStatus{}
+		descriptors[dir] = append(descriptors[dir], c)
+	}
+
+	dirs := make([]string, 0, len(descriptors))
+	for dir := range descriptors {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		descriptor, err := cmdutil.LoadHalkyonDescriptorCreatingIfNeeded(dir, true)
+		if err != nil {
+			return fmt.Errorf("couldn't load existing descriptor in %s: %v", dir, err)
+		}
+		for _, object := range descriptors[dir] {
+			descriptor.Add(object)
+		}
+		if err := descriptor.OutputAt(); err != nil {
+			return err
+		}
+		log.Successf("Exported %d entities to %s", len(descriptors[dir]), filepath.Join(dir, "halkyon.yml"))
+	}
+	return nil
+}
+
+// cleanObjectMeta only keeps the metadata that make sense outside of the cluster the entity was retrieved from
+func cleanObjectMeta(meta v1.ObjectMeta) v1.ObjectMeta {
+	cleaned := v1.ObjectMeta{
+		Name:   meta.Name,
+		Labels: meta.Labels,
+	}
+	for key, value := range meta.Annotations {
+		ignored := false
+		for _, annotation := range ignoredAnnotations {
+			if key == annotation {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			if cleaned.Annotations == nil {
+				cleaned.Annotations = make(map[string]string, len(meta.Annotations))
+			}
+			cleaned.Annotations[key] = value
+		}
+	}
+	return cleaned
+}
+
+func NewCmdExport(parent string) *cobra.Command {
+	o := &options{}
+	export := &cobra.Command{
+		Use:     fmt.Sprintf("%s [flags]", commandName),
+		Short:   "Export components and capabilities from the cluster to halkyon descriptors",
+		Long:    `Export components and capabilities from the cluster to halkyon descriptors, one per component directory, capabilities shared by several components being exported to a root descriptor.`,
+		Example: fmt.Sprintf(exportExample, cmdutil.CommandName(commandName, parent)),
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmdutil.GenericRun(o, cmd, args)
+		},
+	}
+	export.Flags().StringSliceVarP(&o.names, "components", "c", nil, "Only export the specified components and the capabilities they are bound to")
+	export.Flags().StringVarP(&o.output, "output", "o", "", "Directory to export descriptors to, defaults to the current directory")
+	return export
+}
@@ -3,17 +3,32 @@ package cli
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"halkyon.io/hal/pkg/cmdutil"
+	"halkyon.io/hal/pkg/hal/cli/apply"
 	"halkyon.io/hal/pkg/hal/cli/capability"
+	"halkyon.io/hal/pkg/hal/cli/completion"
 	"halkyon.io/hal/pkg/hal/cli/component"
+	"halkyon.io/hal/pkg/hal/cli/diff"
+	"halkyon.io/hal/pkg/hal/cli/export"
 	"halkyon.io/hal/pkg/hal/cli/version"
+	"halkyon.io/hal/pkg/k8s"
+	"halkyon.io/hal/pkg/ui"
 	ktemplates "k8s.io/kubectl/pkg/util/templates"
 )
 
 const commandName = "hal"
 
 var (
-	halExample = ktemplates.Examples(`  # Displays hal help
- %[1]s  --help`)
+	nonInteractive bool
+	assumeYes      bool
+	halExample     = ktemplates.Examples(`  # Displays hal help
+ %[1]s  --help
+
+  # Run a command without ever prompting, e.g. from a CI pipeline
+ %[1]s component delete foo --non-interactive -y
+
+  # Run a command against another namespace than the current context's one
+ %[1]s component list --namespace feature-x`)
 )
 
 func NewCmdHal() *cobra.Command {
@@ -23,11 +38,26 @@ func NewCmdHal() *cobra.Command {
 		Long: fmt.Sprintf(`%s
 Easily create and manage Kubernetes applications using Dekorate and the Halkyon operator.`, version.Version()),
 		Example: fmt.Sprintf(halExample, commandName),
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// prompts require a terminal so fall back to non-interactive mode if we don't have one
+			ui.SetInteractive(!nonInteractive && ui.IsStdinTerminal())
+			ui.SetAssumeYes(assumeYes)
+		},
 	}
+	hal.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "Never prompt: use provided flags or default values, failing if a required value is missing")
+	k8s.AddConfigFlags(hal.PersistentFlags())
+	cmdutil.RegisterFlagCompletion(hal, "context", func(string) []string { return k8s.KnownContexts() })
+	cmdutil.RegisterFlagCompletion(hal, "namespace", func(string) []string { return k8s.KnownNamespaces() })
+	hal.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Automatically confirm actions instead of prompting for confirmation")
 
 	hal.AddCommand(
+		apply.NewCmdApply(commandName),
 		capability.NewCmdCapability(commandName),
 		component.NewCmdComponent(commandName),
+		completion.NewCmdCompletion(commandName),
+		completion.NewCmdComplete(),
+		diff.NewCmdDiff(commandName),
+		export.NewCmdExport(commandName),
 		version.NewCmdVersion(commandName),
 	)
 
@@ -0,0 +1,242 @@
+// Package ignore implements gitignore-like (gitwildmatch) rules to decide which files of a directory tree should be
+// ignored. Rules are read from ignore files that can be located in any directory of the tree and apply to that directory
+// and its descendants.
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Matcher decides whether paths of the directory tree rooted at a given path are ignored
+type Matcher struct {
+	root      string
+	fileNames []string
+	defaults  []rule
+	loaded    map[string][]rule
+}
+
+type rule struct {
+	base    string
+	matcher *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// NewMatcher creates a Matcher for the tree rooted at the specified path, using the specified default patterns (which
+// apply as if they were specified in an ignore file at the root of the tree) and reading rules from ignore files with the
+// specified names, in order, so that rules from later files take precedence over rules from earlier ones.
+func NewMatcher(root string, defaults []string, fileNames ...string) *Matcher {
+	m := &Matcher{
+		root:      root,
+		fileNames: fileNames,
+		loaded:    make(map[string][]rule, 7),
+	}
+	for _, pattern := range defaults {
+		if r, ok := parseRule(pattern, ""); ok {
+			m.defaults = append(m.defaults, r)
+		}
+	}
+	return m
+}
+
+// Ignored determines whether the specified slash-separated path, relative to the root of the tree, is ignored. A path is
+// ignored if it matches the rules applying to it or if one of its parent directories is ignored.
+func (m *Matcher) Ignored(relative string, isDir bool) bool {
+	relative = strings.Trim(relative, "/")
+	if len(relative) == 0 {
+		return false
+	}
+	segments := strings.Split(relative, "/")
+	for i := 1; i < len(segments); i++ {
+		if m.matches(strings.Join(segments[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matches(relative, isDir)
+}
+
+// Walk walks the tree, calling walkFn for each path that is not ignored, with its slash-separated path relative to the
+// root of the tree. Ignored directories are not walked into.
+func (m *Matcher) Walk(walkFn func(path, relative string, info os.FileInfo) error) error {
+	return filepath.Walk(m.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(m.root, path)
+		if err != nil {
+			return err
+		}
+		if relative == "." {
+			return nil
+		}
+		relative = filepath.ToSlash(relative)
+		if m.matches(relative, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return walkFn(path, relative, info)
+	})
+}
+
+// matches checks the rules applying to the specified path, without considering its parents: the last matching rule wins
+func (m *Matcher) matches(relative string, isDir bool) bool {
+	ignored := false
+	check := func(rules []rule) {
+		for _, r := range rules {
+			if r.matches(relative, isDir) {
+				ignored = !r.negate
+			}
+		}
+	}
+
+	check(m.defaults)
+	dir := ""
+	check(m.rulesFor(dir))
+	segments := strings.Split(relative, "/")
+	for _, segment := range segments[:len(segments)-1] {
+		dir = path.Join(dir, segment)
+		check(m.rulesFor(dir))
+	}
+	return ignored
+}
+
+// rulesFor retrieves the rules defined in the ignore files of the specified directory, loading them if needed
+func (m *Matcher) rulesFor(dir string) []rule {
+	if rules, ok := m.loaded[dir]; ok {
+		return rules
+	}
+	rules := make([]rule, 0, 11)
+	for _, name := range m.fileNames {
+		rules = append(rules, readRules(filepath.Join(m.root, filepath.FromSlash(dir), name), dir)...)
+	}
+	m.loaded[dir] = rules
+	return rules
+}
+
+func readRules(file, base string) []rule {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	rules := make([]rule, 0, 11)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if r, ok := parseRule(scanner.Text(), base); ok {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+func parseRule(pattern, base string) (rule, bool) {
+	pattern = strings.TrimRight(pattern, "\r")
+	// trailing spaces are ignored unless escaped
+	for strings.HasSuffix(pattern, " ") && !strings.HasSuffix(pattern, "\\ ") {
+		pattern = pattern[:len(pattern)-1]
+	}
+	if len(pattern) == 0 || strings.HasPrefix(pattern, "#") {
+		return rule{}, false
+	}
+
+	r := rule{base: base}
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, "\\!") || strings.HasPrefix(pattern, "\\#") {
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if len(pattern) == 0 {
+		return rule{}, false
+	}
+
+	// patterns containing a separator are relative to the directory of the ignore file, others match at any level
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	expression := "^"
+	if !anchored {
+		expression += "(?:.*/)?"
+	}
+	expression += globToRegexp(pattern) + "$"
+	matcher, err := regexp.Compile(expression)
+	if err != nil {
+		return rule{}, false
+	}
+	r.matcher = matcher
+	return r, true
+}
+
+func globToRegexp(pattern string) string {
+	var result strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				atSegmentStart := i == 0 || pattern[i-1] == '/'
+				switch {
+				case atSegmentStart && i+2 < len(pattern) && pattern[i+2] == '/':
+					// "**/" matches zero or more directories
+					result.WriteString("(?:.*/)?")
+					i += 2
+				case atSegmentStart && i+2 == len(pattern):
+					// trailing "**" matches everything inside
+					result.WriteString(".*")
+					i++
+				default:
+					result.WriteString("[^/]*")
+					i++
+				}
+			} else {
+				result.WriteString("[^/]*")
+			}
+		case '?':
+			result.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				result.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := pattern[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			result.WriteString("[" + strings.Replace(class, "\\", "\\\\", -1) + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				c = pattern[i]
+			}
+			result.WriteString(regexp.QuoteMeta(string(c)))
+		default:
+			result.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return result.String()
+}
+
+func (r rule) matches(relative string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	if len(r.base) > 0 {
+		if !strings.HasPrefix(relative, r.base+"/") {
+			return false
+		}
+		relative = strings.TrimPrefix(relative, r.base+"/")
+	}
+	return r.matcher.MatchString(relative)
+}
@@ -0,0 +1,102 @@
+package ignore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestMatcher_Ignored(t *testing.T) {
+	root := createTree(t, map[string]string{
+		".gitignore":             "# comment\nnode_modules/\n*.log\n!keep.log\n/build\n.idea\n",
+		".halignore":             "!.mvn/\ndocs/**/*.pdf\n",
+		"module/.gitignore":      "generated/\n/local.txt\n",
+		"module/local.txt":       "",
+		"module/other/local.txt": "",
+	})
+	defer os.RemoveAll(root)
+	m := NewMatcher(root, []string{".git/", "target/", ".mvn/"}, ".gitignore", ".halignore")
+
+	tests := []struct {
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{path: "src/main/java/App.java", ignored: false},
+		{path: "node_modules", isDir: true, ignored: true},
+		{path: "frontend/node_modules/lib/index.js", ignored: true},
+		{path: "node_modules", ignored: false},
+		{path: "app.log", ignored: true},
+		{path: "logs/app.log", ignored: true},
+		{path: "keep.log", ignored: false},
+		{path: "build", isDir: true, ignored: true},
+		{path: "module/build", isDir: true, ignored: false},
+		{path: ".idea/workspace.xml", ignored: true},
+		{path: ".git/config", ignored: true},
+		{path: "module/target/classes/App.class", ignored: true},
+		{path: ".mvn/wrapper/maven-wrapper.properties", ignored: false},
+		{path: "docs/guide/manual.pdf", ignored: true},
+		{path: "docs/manual.pdf", ignored: true},
+		{path: "docs/manual.adoc", ignored: false},
+		{path: "module/generated/Foo.java", ignored: true},
+		{path: "generated/Foo.java", ignored: false},
+		{path: "module/local.txt", ignored: true},
+		{path: "module/other/local.txt", ignored: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := m.Ignored(tt.path, tt.isDir); got != tt.ignored {
+				t.Errorf("Expected ignored = %v, But got = %v", tt.ignored, got)
+			}
+		})
+	}
+}
+
+func TestMatcher_Walk(t *testing.T) {
+	root := createTree(t, map[string]string{
+		".gitignore":        "*.tmp\nout/\n",
+		".mvn/wrapper/jar":  "",
+		"pom.xml":           "",
+		"src/App.java":      "",
+		"src/App.tmp":       "",
+		"out/classes/A.txt": "",
+	})
+	defer os.RemoveAll(root)
+	m := NewMatcher(root, nil, ".gitignore")
+
+	files := make([]string, 0, 7)
+	err := m.Walk(func(path, relative string, info os.FileInfo) error {
+		if !info.IsDir() {
+			files = append(files, relative)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	expected := []string{".gitignore", ".mvn/wrapper/jar", "pom.xml", "src/App.java"}
+	if !reflect.DeepEqual(expected, files) {
+		t.Errorf("Expected %v, But got %v", expected, files)
+	}
+}
+
+func createTree(t *testing.T, files map[string]string) string {
+	root, err := ioutil.TempDir("", "hal-ignore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
 	capInfo "halkyon.io/api/capability-info/clientset/versioned/typed/capability-info/v1beta1"
 	capability "halkyon.io/api/capability/clientset/versioned/typed/capability/v1beta1"
 	component "halkyon.io/api/component/clientset/versioned/typed/component/v1beta1"
@@ -23,6 +24,7 @@ import (
 	"k8s.io/client-go/tools/remotecommand"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -42,41 +44,123 @@ type Client struct {
 	Namespace                   string
 }
 
-var client *Client
+var (
+	client *Client
+	// clientLock guards the lazy initialization of client, which can be retrieved from concurrent goroutines
+	clientLock sync.Mutex
+	// kubeConfigPath and configOverrides are set from the command line to target another cluster, context or namespace
+	// than the current kubeconfig's
+	kubeConfigPath  string
+	configOverrides clientcmd.ConfigOverrides
+)
+
+// AddConfigFlags adds the flags allowing to select which kubeconfig, context and namespace the client uses to the
+// specified flag set. They need to be parsed before the client is first retrieved.
+func AddConfigFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&kubeConfigPath, "kubeconfig", "", "Path to the kubeconfig file to use instead of the default one")
+	flags.StringVar(&configOverrides.CurrentContext, "context", "", "Name of the kubeconfig context to use instead of the current one")
+	flags.StringVar(&configOverrides.Context.Namespace, "namespace", "", "Namespace to use instead of the current context's one")
+}
 
-// GetClient retrieves a client
+// KnownContexts returns the names of the contexts defined in the kubeconfig
+func KnownContexts() []string {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfigPath
+	config, err := loadingRules.Load()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		names = append(names, name)
+	}
+	return names
+}
+
+// KnownNamespaces returns the names of the namespaces the user can list, if any
+func KnownNamespaces() []string {
+	c, err := NewClient()
+	if err != nil {
+		return nil
+	}
+	list, err := c.KubeClient.CoreV1().Namespaces().List(metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, namespace := range list.Items {
+		names = append(names, namespace.Name)
+	}
+	return names
+}
+
+// GetClient retrieves a client, exiting if the cluster configuration cannot be loaded
 func GetClient() *Client {
+	clientLock.Lock()
+	defer clientLock.Unlock()
 	if client == nil {
-		// initialize client-go clients
-		client = &Client{}
-		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-		configOverrides := &clientcmd.ConfigOverrides{}
-		client.KubeConfig = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-		config, err := client.KubeConfig.ClientConfig()
-		io2.LogErrorAndExit(err, "error creating k8s config")
+		c, err := NewClient()
+		io2.LogErrorAndExit(err, "")
+		client = c
+	}
+
+	return client
+}
 
-		kubeClient, err := kubernetes.NewForConfig(config)
-		io2.LogErrorAndExit(err, "error creating k8s client")
-		client.KubeClient = kubeClient
+// NewClient initializes a new client from the current cluster configuration, returning an error instead of exiting if
+// it cannot be loaded so that callers can fall back to working offline
+func NewClient() (*Client, error) {
+	// initialize client-go clients
+	c := &Client{}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeConfigPath
+	overrides := configOverrides
+	c.KubeConfig = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &overrides)
+	config, err := c.KubeConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error creating k8s config: %v", err)
+	}
 
-		client.HalkyonComponentClient, err = component.NewForConfig(config)
-		io2.LogErrorAndExit(err, "error creating halkyon component client")
+	c.KubeClient, err = kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating k8s client: %v", err)
+	}
 
-		client.HalkyonCapabilityClient, err = capability.NewForConfig(config)
-		io2.LogErrorAndExit(err, "error creating halkyon capability client")
+	c.HalkyonComponentClient, err = component.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating halkyon component client: %v", err)
+	}
 
-		client.HalkyonRuntimeClient, err = hruntime.NewForConfig(config)
-		io2.LogErrorAndExit(err, "error creating halkyon runtime client")
+	c.HalkyonCapabilityClient, err = capability.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating halkyon capability client: %v", err)
+	}
 
-		client.HalkyonCapabilityInfoClient, err = capInfo.NewForConfig(config)
-		io2.LogErrorAndExit(err, "error creating halkyon capability info client")
+	c.HalkyonRuntimeClient, err = hruntime.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating halkyon runtime client: %v", err)
+	}
 
-		namespace, _, err := client.KubeConfig.Namespace()
-		io2.LogErrorAndExit(err, "error retrieving namespace")
-		client.Namespace = namespace
+	c.HalkyonCapabilityInfoClient, err = capInfo.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating halkyon capability info client: %v", err)
 	}
 
-	return client
+	c.Namespace, _, err = c.KubeConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving namespace: %v", err)
+	}
+
+	return c, nil
+}
+
+// Server returns the address of the cluster this client connects to
+func (c *Client) Server() string {
+	config, err := c.KubeConfig.ClientConfig()
+	if err != nil {
+		return ""
+	}
+	return config.Host
 }
 
 func (c *Client) ExecCommand(podName string, cmd []string, statusMsg string) error {
@@ -110,7 +194,37 @@ func (c *Client) ExecCommand(podName string, cmd []string, statusMsg string) err
 
 // ExecCMDInContainer execute command in first container of a pod
 func (c *Client) ExecCMDInContainer(podName string, cmd []string, stdout io.Writer, stderr io.Writer, stdin io.Reader, tty bool) error {
+	err := c.exec(podName, cmd, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+		Tty:    tty,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error while streaming command")
+	}
+	return nil
+}
 
+// ExecInteractive executes the specified command in the first container of the specified pod, attaching the provided
+// streams. A TTY is allocated if a TerminalSizeQueue is provided, size changes being propagated to the container. Errors
+// reporting the exit status of the command are returned unwrapped so that it can be passed through.
+func (c *Client) ExecInteractive(podName string, cmd []string, stdin io.Reader, stdout, stderr io.Writer, sizes remotecommand.TerminalSizeQueue) error {
+	options := remotecommand.StreamOptions{
+		Stdin:             stdin,
+		Stdout:            stdout,
+		Stderr:            stderr,
+		Tty:               sizes != nil,
+		TerminalSizeQueue: sizes,
+	}
+	if options.Tty {
+		// the TTY merges stderr into stdout
+		options.Stderr = nil
+	}
+	return c.exec(podName, cmd, options)
+}
+
+func (c *Client) exec(podName string, cmd []string, options remotecommand.StreamOptions) error {
 	req := c.KubeClient.CoreV1().RESTClient().
 		Post().
 		Namespace(c.Namespace).
@@ -119,10 +233,10 @@ func (c *Client) ExecCMDInContainer(podName string, cmd []string, stdout io.Writ
 		SubResource("exec").
 		VersionedParams(&corev1.PodExecOptions{
 			Command: cmd,
-			Stdin:   stdin != nil,
-			Stdout:  stdout != nil,
-			Stderr:  stderr != nil,
-			TTY:     tty,
+			Stdin:   options.Stdin != nil,
+			Stdout:  options.Stdout != nil,
+			Stderr:  options.Stderr != nil,
+			TTY:     options.Tty,
 		}, scheme.ParameterCodec)
 
 	config, err := c.KubeConfig.ClientConfig()
@@ -136,22 +250,17 @@ func (c *Client) ExecCMDInContainer(podName string, cmd []string, stdout io.Writ
 		return errors.Wrapf(err, "unable execute command via SPDY")
 	}
 	// initialize the transport of the standard shell streams
-	err = exec.Stream(remotecommand.StreamOptions{
-		Stdin:  stdin,
-		Stdout: stdout,
-		Stderr: stderr,
-		Tty:    tty,
-	})
-	if err != nil {
-		return errors.Wrapf(err, "error while streaming command")
-	}
-
-	return nil
+	return exec.Stream(options)
 }
 
+// WaitForComponent waits for the named component to reach the desired phase, displaying a spinner with the specified
+// message unless it is empty
 func (c *Client) WaitForComponent(name string, desiredPhase string, waitMessage string) (*v1beta1.Component, error) {
-	s := log2.Spinner(waitMessage)
-	defer s.End(false)
+	var s *log2.Status
+	if len(waitMessage) > 0 {
+		s = log2.Spinner(waitMessage)
+		defer s.End(false)
+	}
 
 	var timeout int64 = timeoutDuration
 	w, err := c.HalkyonComponentClient.
@@ -187,7 +296,9 @@ func (c *Client) WaitForComponent(name string, desiredPhase string, waitMessage
 			if e, ok := object.(*v1beta1.Component); ok {
 				switch e.Status.Reason {
 				case desiredPhase:
-					s.End(true)
+					if s != nil {
+						s.End(true)
+					}
 					podChannel <- e
 					break loop
 				case v1beta12.ReasonFailed:
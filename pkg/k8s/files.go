@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"archive/tar"
+	"bytes"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Copy uploads the file or directory at the specified path to the specified directory of the specified pod
+func (c *Client) Copy(path, podName, directory string) error {
+	// wrap the file or directory in a tar archive streamed to the container
+	reader, writer := io.Pipe()
+	go func() {
+		_ = writer.CloseWithError(writeArchive(path, writer))
+	}()
+	return c.Extract(podName, reader, directory)
+}
+
+// ExtractFile uploads the tar archive at the specified path to the specified pod, extracting it in the specified
+// directory
+func (c *Client) ExtractFile(path, podName, directory string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return c.Extract(podName, file, directory)
+}
+
+// Extract streams the tar archive provided by the specified reader to the specified pod, extracting it in the specified
+// directory
+func (c *Client) Extract(podName string, archive io.Reader, directory string) error {
+	var stderr bytes.Buffer
+	err := c.ExecCMDInContainer(podName, []string{"tar", "xmf", "-", "-C", directory}, nil, &stderr, archive, false)
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); len(msg) > 0 {
+			return errors.Wrapf(err, "couldn't extract archive in '%s' pod's %s directory: %s", podName, directory, msg)
+		}
+		return errors.Wrapf(err, "couldn't extract archive in '%s' pod's %s directory", podName, directory)
+	}
+	return nil
+}
+
+// writeArchive writes a tar archive of the file or directory at the specified path, entries being named relatively to
+// its parent directory
+func writeArchive(root string, out io.Writer) error {
+	parent := filepath.Dir(root)
+	writer := tar.NewWriter(out)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			// skip symbolic links and other special files
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(parent, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := writer.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(writer, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Close()
+}
+
+// IsPresent checks whether the specified path exists in the specified pod
+func (c *Client) IsPresent(podName, path string) bool {
+	return c.ExecCMDInContainer(podName, []string{"ls", path}, ioutil.Discard, ioutil.Discard, nil, false) == nil
+}
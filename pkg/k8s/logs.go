@@ -0,0 +1,29 @@
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	"io"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Logs streams the logs of the specified pod, as configured by the specified options, to the specified writer. When
+// following logs, this function only returns once the stream is closed by the cluster.
+func (c *Client) Logs(podName string, options *corev1.PodLogOptions, out io.Writer) error {
+	stream, err := c.LogStream(podName, options)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	_, err = io.Copy(out, stream)
+	return err
+}
+
+// LogStream opens a stream on the logs of the specified pod, as configured by the specified options. Closing the stream
+// stops following the logs.
+func (c *Client) LogStream(podName string, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	stream, err := c.KubeClient.CoreV1().Pods(c.Namespace).GetLogs(podName, options).Stream()
+	if err != nil {
+		return nil, errors.Wrapf(err, "couldn't retrieve logs for '%s' pod", podName)
+	}
+	return stream, nil
+}
@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"github.com/pkg/errors"
+	"io"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"net/http"
+)
+
+// PortForward forwards the specified ports, using the "local:remote" format, to the specified pod until the stop channel
+// is closed or the connection to the pod is lost. The ready channel is closed once local ports are listened to.
+func (c *Client) PortForward(podName string, ports []string, stop <-chan struct{}, ready chan struct{}, out, errOut io.Writer) error {
+	config, err := c.KubeConfig.ClientConfig()
+	if err != nil {
+		return errors.Wrapf(err, "unable to get Kubernetes client config")
+	}
+	// use the same SPDY transport as when executing commands in containers
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create SPDY transport")
+	}
+	req := c.KubeClient.CoreV1().RESTClient().
+		Post().
+		Namespace(c.Namespace).
+		Resource("pods").
+		Name(podName).
+		SubResource("portforward")
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	forwarder, err := portforward.New(dialer, ports, stop, ready, out, errOut)
+	if err != nil {
+		return errors.Wrapf(err, "unable to forward ports to '%s' pod", podName)
+	}
+	return forwarder.ForwardPorts()
+}
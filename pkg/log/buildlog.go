@@ -0,0 +1,119 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/fatih/color"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	// errorLinePattern matches lines reporting errors in Maven, Gradle or compiler output
+	errorLinePattern = regexp.MustCompile(`^\s*(\[ERROR]|ERROR:|FAILURE:|BUILD FAILURE|e: |error: )|: error: `)
+	// errorLocationPattern matches source locations as reported by javac through Maven (File.java:[12,5]), javac
+	// (File.java:12:), kotlinc through Gradle (File.kt: (12, 5)) and most other compilers (file.go:12:5:)
+	errorLocationPattern = regexp.MustCompile(`[\w./\\-]+\.\w+(:\[\d+,\d+]|: \(\d+, \d+\)|:\d+(:\d+)?)`)
+)
+
+// BuildOutput records the last lines of a build's output, optionally echoing them as they are written, so that the tail
+// of the output can be displayed, with errors highlighted, if the build fails. It can safely be written to from several
+// goroutines.
+type BuildOutput struct {
+	mutex   sync.Mutex
+	echo    io.Writer
+	max     int
+	lines   []string
+	partial bytes.Buffer
+}
+
+// NewBuildOutput creates a BuildOutput recording up to max lines and echoing them to the specified writer, if not nil
+func NewBuildOutput(max int, echo io.Writer) *BuildOutput {
+	return &BuildOutput{echo: echo, max: max, lines: make([]string, 0, max)}
+}
+
+func (b *BuildOutput) Write(p []byte) (n int, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.partial.Write(p)
+	for {
+		line, err := b.partial.ReadString('\n')
+		if err != nil {
+			// keep incomplete line until we get the rest of it
+			b.partial.Reset()
+			b.partial.WriteString(line)
+			break
+		}
+		b.add(strings.TrimRight(line, "\r\n"))
+	}
+	return len(p), nil
+}
+
+// Flush records the last line if it wasn't terminated by a new line
+func (b *BuildOutput) Flush() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.partial.Len() > 0 {
+		b.add(b.partial.String())
+		b.partial.Reset()
+	}
+}
+
+func (b *BuildOutput) add(line string) {
+	if len(b.lines) == b.max {
+		b.lines = append(b.lines[:0], b.lines[1:]...)
+	}
+	b.lines = append(b.lines, line)
+	if b.echo != nil {
+		fmt.Fprintf(b.echo, "%s%s\n", prefixSpacing, Highlight(line))
+	}
+}
+
+// Tail returns the recorded lines
+func (b *BuildOutput) Tail() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]string(nil), b.lines...)
+}
+
+// PrintTail outputs the recorded lines, highlighting errors, to the specified writer
+func (b *BuildOutput) PrintTail(w io.Writer) {
+	b.Flush()
+	for _, line := range b.Tail() {
+		fmt.Fprintf(w, "%s%s\n", prefixSpacing, Highlight(line))
+	}
+}
+
+// IsErrorLine checks whether the specified build output line reports an error
+func IsErrorLine(line string) bool {
+	return errorLinePattern.MatchString(line)
+}
+
+// ErrorLocations returns the start and end indices of source locations found in the specified line if it reports an
+// error
+func ErrorLocations(line string) [][]int {
+	if !IsErrorLine(line) {
+		return nil
+	}
+	return errorLocationPattern.FindAllStringIndex(line, -1)
+}
+
+// Highlight displays lines reporting errors in red, with source locations in bold
+func Highlight(line string) string {
+	if !IsErrorLine(line) {
+		return line
+	}
+	red := color.New(color.FgRed).SprintFunc()
+	location := color.New(color.FgRed, color.Bold).SprintFunc()
+	var highlighted strings.Builder
+	last := 0
+	for _, indices := range ErrorLocations(line) {
+		highlighted.WriteString(red(line[last:indices[0]]))
+		highlighted.WriteString(location(line[indices[0]:indices[1]]))
+		last = indices[1]
+	}
+	highlighted.WriteString(red(line[last:]))
+	return highlighted.String()
+}
@@ -0,0 +1,54 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestBuildOutputKeepsLastLines(t *testing.T) {
+	output := NewBuildOutput(3, nil)
+	fmt.Fprint(output, "1\n2\n")
+	fmt.Fprint(output, "3\n4")
+	if tail := output.Tail(); !reflect.DeepEqual(tail, []string{"1", "2", "3"}) {
+		t.Errorf("incomplete line shouldn't be recorded, got %v", tail)
+	}
+	fmt.Fprint(output, "5\n6")
+	output.Flush()
+	if tail := output.Tail(); !reflect.DeepEqual(tail, []string{"3", "45", "6"}) {
+		t.Errorf("expected last 3 lines, got %v", tail)
+	}
+}
+
+func TestBuildOutputEchoesLines(t *testing.T) {
+	var echo bytes.Buffer
+	output := NewBuildOutput(1, &echo)
+	fmt.Fprint(output, "[INFO] Building\r\n[INFO] Compiling")
+	if echo.String() != prefixSpacing+"[INFO] Building\n" {
+		t.Errorf("only complete lines should be echoed, got %q", echo.String())
+	}
+}
+
+func TestErrorLocations(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected []string
+	}{
+		{"[INFO] Compiling 3 source files to /usr/src/target/classes", nil},
+		{"[ERROR] /usr/src/src/main/java/dev/Foo.java:[12,5] cannot find symbol", []string{"/usr/src/src/main/java/dev/Foo.java:[12,5]"}},
+		{"src/main/java/dev/Foo.java:12: error: ';' expected", []string{"src/main/java/dev/Foo.java:12"}},
+		{"e: /usr/src/src/main/kotlin/Foo.kt: (3, 10): unresolved reference: bar", []string{"/usr/src/src/main/kotlin/Foo.kt: (3, 10)"}},
+		{"[ERROR] Failed to execute goal on project foo", nil},
+		{"Foo.java:12 is mentioned in an info line", nil},
+	}
+	for _, tt := range tests {
+		locations := make([]string, 0, len(tt.expected))
+		for _, indices := range ErrorLocations(tt.line) {
+			locations = append(locations, tt.line[indices[0]:indices[1]])
+		}
+		if len(locations) != len(tt.expected) || (len(locations) > 0 && !reflect.DeepEqual(locations, tt.expected)) {
+			t.Errorf("expected %v locations in %q, got %v", tt.expected, tt.line, locations)
+		}
+	}
+}
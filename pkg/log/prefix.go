@@ -0,0 +1,82 @@
+package log
+
+import (
+	"bytes"
+	"github.com/fatih/color"
+	"io"
+	"sync"
+)
+
+var prefixColors = []color.Attribute{color.FgCyan, color.FgMagenta, color.FgYellow, color.FgGreen, color.FgBlue, color.FgRed}
+
+// PrefixWriter writes lines to an underlying writer, prefixing each of them with a colored prefix. Several PrefixWriters
+// can share the same underlying writer, in which case complete lines are written atomically so that they can be
+// interleaved safely. A PrefixWriter can itself be written to concurrently.
+type PrefixWriter struct {
+	out    io.Writer
+	lock   *sync.Mutex
+	prefix string
+	// pendingLock guards pending, the incomplete line buffered until its end is written
+	pendingLock sync.Mutex
+	pending     []byte
+}
+
+var _ io.Writer = &PrefixWriter{}
+
+// NewPrefixWriters creates a PrefixWriter for each specified prefix, all sharing the specified writer and each using a
+// different color
+func NewPrefixWriters(out io.Writer, prefixes ...string) []*PrefixWriter {
+	lock := &sync.Mutex{}
+	width := 0
+	for _, prefix := range prefixes {
+		if len(prefix) > width {
+			width = len(prefix)
+		}
+	}
+	writers := make([]*PrefixWriter, 0, len(prefixes))
+	for i, prefix := range prefixes {
+		padded := prefix + string(bytes.Repeat([]byte(" "), width-len(prefix)))
+		colored := color.New(prefixColors[i%len(prefixColors)], color.Bold).Sprint(padded + " | ")
+		writers = append(writers, &PrefixWriter{out: out, lock: lock, prefix: colored})
+	}
+	return writers
+}
+
+func (w *PrefixWriter) Write(p []byte) (n int, err error) {
+	w.pendingLock.Lock()
+	defer w.pendingLock.Unlock()
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.writeLine(w.pending[:i+1]); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes any incomplete line that remains buffered
+func (w *PrefixWriter) Flush() error {
+	w.pendingLock.Lock()
+	defer w.pendingLock.Unlock()
+	if len(w.pending) == 0 {
+		return nil
+	}
+	line := append(w.pending, '\n')
+	w.pending = nil
+	return w.writeLine(line)
+}
+
+func (w *PrefixWriter) writeLine(line []byte) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if _, err := io.WriteString(w.out, w.prefix); err != nil {
+		return err
+	}
+	_, err := w.out.Write(line)
+	return err
+}
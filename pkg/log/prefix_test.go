@@ -0,0 +1,52 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/fatih/color"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrefixWriterSupportsConcurrentWrites(t *testing.T) {
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+	color.NoColor = true
+	var out bytes.Buffer
+	w := NewPrefixWriters(&out, "a")[0]
+
+	const lines = 200
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, source := range []string{"logs", "steps"} {
+		wg.Add(1)
+		go func(source string) {
+			defer wg.Done()
+			<-start
+			for i := 0; i < lines; i++ {
+				// write lines in several parts to exercise the buffering of incomplete lines
+				fmt.Fprintf(w, "%s ", source)
+				// let the other goroutine write, even on a single CPU
+				runtime.Gosched()
+				fmt.Fprintf(w, "%d\n", i)
+			}
+		}(source)
+	}
+	close(start)
+	wg.Wait()
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	written := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(written) != 2*lines {
+		t.Fatalf("expected %d lines, got %d", 2*lines, len(written))
+	}
+	for _, line := range written {
+		if !strings.HasPrefix(line, "a | ") {
+			t.Errorf("line isn't prefixed: %q", line)
+		}
+	}
+}
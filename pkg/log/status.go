@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"halkyon.io/hal/pkg/log/fidget"
 	"io"
+	"io/ioutil"
 	"os"
 	"runtime"
 	"strings"
@@ -41,21 +42,10 @@ const suffixSpacing = "  "
 const prefixSpacing = " "
 
 var (
-	stdErr         = colorable.NewColorableStderr()
-	stdOut         = colorable.NewColorableStdout()
-	errInterceptor = &ErrorInterceptor{stdErr: stdErr}
+	stdErr = colorable.NewColorableStderr()
+	stdOut = colorable.NewColorableStdout()
 )
 
-type ErrorInterceptor struct {
-	stdErr   io.Writer
-	ErrorMsg string
-}
-
-func (l *ErrorInterceptor) Write(p []byte) (n int, err error) {
-	l.ErrorMsg = string(p)
-	return len(p), nil
-}
-
 // Status is used to track ongoing status in a CLI, with a nice loading spinner
 // when attached to a terminal
 type Status struct {
@@ -181,14 +171,24 @@ func Progressf(format string, a ...interface{}) {
 
 // Successf will output in an appropriate "progress" manner
 func Successf(format string, a ...interface{}) {
+	Fsuccessf(GetStdout(), format, a...)
+}
+
+// Fsuccessf is the same as Successf but outputs to the specified writer
+func Fsuccessf(w io.Writer, format string, a ...interface{}) {
 	green := color.New(color.FgGreen).SprintFunc()
-	fmt.Fprintf(GetStdout(), "%s%s%s%s\n", prefixSpacing, green(getSuccessString()), suffixSpacing, fmt.Sprintf(format, a...))
+	fmt.Fprintf(w, "%s%s%s%s\n", prefixSpacing, green(getSuccessString()), suffixSpacing, fmt.Sprintf(format, a...))
 }
 
 // Errorf will output in an appropriate "progress" manner
 func Errorf(format string, a ...interface{}) {
+	Ferrorf(GetStderr(), format, a...)
+}
+
+// Ferrorf is the same as Errorf but outputs to the specified writer
+func Ferrorf(w io.Writer, format string, a ...interface{}) {
 	red := color.New(color.FgRed).SprintFunc()
-	fmt.Fprintf(GetStderr(), " %s%s%s\n", red(getErrString()), suffixSpacing, fmt.Sprintf(format, a...))
+	fmt.Fprintf(w, " %s%s%s\n", red(getErrString()), suffixSpacing, fmt.Sprintf(format, a...))
 }
 
 // Error will output in an appropriate "progress" manner
@@ -207,8 +207,13 @@ func Info(a ...interface{}) {
 // Infof will simply print out information on a new (bolded) line
 // this is intended as information *after* something has been deployed
 func Infof(format string, a ...interface{}) {
+	Finfof(GetStdout(), format, a...)
+}
+
+// Finfof is the same as Infof but outputs to the specified writer
+func Finfof(w io.Writer, format string, a ...interface{}) {
 	bold := color.New(color.Bold).SprintFunc()
-	fmt.Fprintf(GetStdout(), "%s\n", bold(fmt.Sprintf(format, a...)))
+	fmt.Fprintf(w, "%s\n", bold(fmt.Sprintf(format, a...)))
 }
 
 // Askf will print out information, but in an "Ask" way (without newline)
@@ -255,6 +260,12 @@ func IsDebug() bool {
 	return false
 }
 
+// Silence discards all output, e.g. when the command output needs to be processed by another program
+func Silence() {
+	stdOut = ioutil.Discard
+	stdErr = ioutil.Discard
+}
+
 // GetStdout gets the appropriate stdout from the OS.
 func GetStdout() io.Writer {
 	return stdOut
@@ -265,10 +276,6 @@ func GetStderr() io.Writer {
 	return stdErr
 }
 
-func GetErrorInterceptor() *ErrorInterceptor {
-	return errInterceptor
-}
-
 // getErrString returns a certain string based upon the OS.
 // Some Windows terminals do not support unicode and must use ASCII.
 // TODO: Test needs to be added once we get Windows testing available on TravisCI / CI platform.
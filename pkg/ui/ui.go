@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"github.com/mgutz/ansi"
+	sshterminal "golang.org/x/crypto/ssh/terminal"
 	"gopkg.in/AlecAivazis/survey.v1"
 	"gopkg.in/AlecAivazis/survey.v1/core"
 	"gopkg.in/AlecAivazis/survey.v1/terminal"
@@ -12,6 +13,53 @@ import (
 	"strings"
 )
 
+var (
+	interactive = true
+	assumeYes   = false
+)
+
+// SetInteractive configures whether prompts can be displayed. When not interactive, prompts resolve to their provided
+// or default value, or return a PromptError explaining which value is missing.
+func SetInteractive(value bool) {
+	interactive = value
+}
+
+// IsInteractive returns whether prompts can be displayed
+func IsInteractive() bool {
+	return interactive
+}
+
+// SetAssumeYes configures whether confirmations are automatically accepted
+func SetAssumeYes(value bool) {
+	assumeYes = value
+}
+
+// IsStdinTerminal returns whether the standard input is attached to a terminal, which is required to display prompts
+func IsStdinTerminal() bool {
+	return sshterminal.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// PromptError reports a value that cannot be resolved without prompting the user
+type PromptError struct {
+	// Prompt is the message of the prompt that couldn't be displayed
+	Prompt string
+	// Flag is the name of the flag that can be used to provide the value instead, if known
+	Flag    string
+	message string
+}
+
+func (e *PromptError) Error() string {
+	if len(e.Flag) > 0 {
+		return fmt.Sprintf("%s, use --%s to provide it", e.message, e.Flag)
+	}
+	return e.message
+}
+
+// fail reports a value that cannot be resolved without displaying the specified prompt
+func fail(prompt string, format string, a ...interface{}) error {
+	return &PromptError{Prompt: prompt, message: fmt.Sprintf(format, a...)}
+}
+
 // HandleError handles UI-related errors, in particular useful to gracefully handle ctrl-c interrupts gracefully
 func HandleError(err error) {
 	if err != nil {
@@ -23,8 +71,13 @@ func HandleError(err error) {
 	}
 }
 
-// Proceed displays a given message and asks the user if they want to proceed
+// Proceed displays a given message and asks the user if they want to proceed. When not interactive, false is returned
+// without failing so that optional steps guarded by such questions are skipped: callers relying on it to decide
+// whether to perform a required step need to handle that case, typically by using Confirm instead.
 func Proceed(message string) bool {
+	if !interactive {
+		return false
+	}
 	var response bool
 	prompt := &survey.Confirm{
 		Message: message,
@@ -37,20 +90,48 @@ func Proceed(message string) bool {
 	return response
 }
 
-func Select(message string, options []string, defaultValue ...string) string {
+// Confirm asks the user to confirm an action. Confirmations are automatically accepted when requested, otherwise a
+// PromptError is returned when not interactive since the action cannot be confirmed.
+func Confirm(message string) (bool, error) {
+	if assumeYes {
+		OutputSelection(message, "yes")
+		return true, nil
+	}
+	if !interactive {
+		return false, fail(message, "%s: confirmation required, use -y to confirm when running non-interactively", message)
+	}
+	return Proceed(message), nil
+}
+
+func Select(message string, options []string, defaultValue ...string) (string, error) {
 	sort.Strings(options)
 	return doSelect(message, options, defaultValue)
 }
 
-func SelectDisplayable(message string, options DisplayableMap, defaultValue ...string) Displayable {
+func SelectDisplayable(message string, options DisplayableMap, defaultValue ...string) (Displayable, error) {
+	if options.Len() == 0 {
+		return nil, fail(message, "%s: no available value to choose from", message)
+	}
 	sort.Sort(options)
 	displayableOptions := options.asDisplayableOptions()
-	display := doSelect(message, displayableOptions, defaultValue)
+	display, err := doSelect(message, displayableOptions, defaultValue)
+	if err != nil {
+		return nil, err
+	}
 	displayable, _ := options.GetByDisplay(display)
-	return displayable
+	return displayable, nil
 }
 
-func doSelect(message string, options []string, defaultValue []string) string {
+func doSelect(message string, options []string, defaultValue []string) (string, error) {
+	if !interactive {
+		if len(defaultValue) == 1 && len(defaultValue[0]) > 0 {
+			return defaultValue[0], nil
+		}
+		if len(options) == 1 {
+			return options[0], nil
+		}
+		return "", fail(message, "missing value for '%s', possible values are: %s", message, strings.Join(options, ", "))
+	}
 	prompt := &survey.Select{
 		Message: message,
 		Options: options,
@@ -58,10 +139,13 @@ func doSelect(message string, options []string, defaultValue []string) string {
 	if len(defaultValue) == 1 {
 		prompt.Default = defaultValue[0]
 	}
-	return askOne(prompt, survey.Required)
+	return askOne(prompt, survey.Required), nil
 }
 
 func MultiSelect(message string, options []string, defaultValues []string) []string {
+	if !interactive {
+		return defaultValues
+	}
 	sort.Strings(options)
 	modules := []string{}
 	prompt := &survey.MultiSelect{
@@ -83,10 +167,13 @@ func AskOrReturnToExit(message string, defaultValue ...string) string {
 		input.Default = defaultValue[0]
 	}
 
+	if !interactive {
+		return input.Default
+	}
 	return askOne(input, validation.NilValidator)
 }
 
-func Ask(message, provided string, defaultValue ...string) string {
+func Ask(message, provided string, defaultValue ...string) (string, error) {
 	input := &survey.Input{
 		Message: message,
 	}
@@ -98,9 +185,16 @@ func Ask(message, provided string, defaultValue ...string) string {
 	if len(provided) > 0 && provided != "0" {
 		// todo: validate provided and ask if value is invalid
 		OutputSelection("Selected "+message, provided)
-		return provided
+		return provided, nil
+	}
+	if !interactive {
+		if len(input.Default) == 0 {
+			return "", fail(message, "missing value for '%s', required when running non-interactively", message)
+		}
+		OutputSelection("Selected default "+strings.ToLower(message), input.Default)
+		return input.Default, nil
 	}
-	return askOne(input, survey.Required)
+	return askOne(input, survey.Required), nil
 }
 
 func askOne(prompt survey.Prompt, validator survey.Validator, stdio ...terminal.Stdio) string {
@@ -138,23 +232,27 @@ func SelectFromOtherErrorMessage(msg, wrong string) string {
 	return fmt.Sprintf("%s%s: %s%s\nSelect other(s) from:", ansi.Red, msg, wrong, ansi.ColorCode("default"))
 }
 
-func SelectOrCheckExisting(parameterValue *string, capitalizedParameterName string, validValues []string, validator func() bool) {
+func SelectOrCheckExisting(parameterValue *string, capitalizedParameterName string, validValues []string, validator func() bool) (err error) {
 	lowerCaseParameterName := strings.ToLower(capitalizedParameterName)
 	if len(*parameterValue) == 0 {
 		if len(validValues) == 1 {
 			*parameterValue = validValues[0]
 			OutputSelection("Automatically selected only available "+lowerCaseParameterName, *parameterValue)
-			return
+			return nil
 		}
-		*parameterValue = Select(capitalizedParameterName, validValues)
+		*parameterValue, err = Select(capitalizedParameterName, validValues)
 	} else {
 		if !validator() {
+			if !interactive {
+				return fail(capitalizedParameterName, "unknown %s '%s', possible values are: %s", lowerCaseParameterName, *parameterValue, strings.Join(validValues, ", "))
+			}
 			s := SelectFromOtherErrorMessage("Unknown "+lowerCaseParameterName, *parameterValue)
-			Select(s, validValues)
+			*parameterValue, err = Select(s, validValues)
 		} else {
 			OutputSelection("Selected "+lowerCaseParameterName, *parameterValue)
 		}
 	}
+	return err
 }
 
 func init() {
@@ -3,6 +3,7 @@ package validation
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 )
 
@@ -120,3 +121,13 @@ func (e *EnumValue) acceptValue(value interface{}) error {
 
 	return e.Contains(value)
 }
+
+// KnownValues returns the sorted list of values accepted by this EnumValue
+func (e EnumValue) KnownValues() []string {
+	values := make([]string, 0, len(e.values))
+	for value := range e.values {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}